@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+// parseSampleNumbers parses the comma-separated numbers of samples like '2,4'.
+func parseSampleNumbers(s string) (map[int]bool, error) {
+	numbers := make(map[int]bool)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid sample number '%s'. specify the numbers of the samples like '2,4'", field)
+		}
+		numbers[n] = true
+	}
+	return numbers, nil
+}
+
+// countFailures returns the number of the samples which did not pass, and the number of those allowed by -allow-fail.
+func (a *App) countFailures(results []atcoder.Result) (failed, allowed int) {
+	for _, result := range results {
+		if result.Success() {
+			continue
+		}
+		if a.allowFail[result.Number()] {
+			allowed++
+		} else {
+			failed++
+		}
+	}
+	return failed, allowed
+}
+
+// printAllowedFailures prints the samples which failed but are allowed by -allow-fail, apart from the results.
+func (a *App) printAllowedFailures(results []atcoder.Result) {
+	var numbers []string
+	for _, result := range results {
+		if !result.Success() && a.allowFail[result.Number()] {
+			numbers = append(numbers, strconv.Itoa(result.Number()))
+		}
+	}
+	if len(numbers) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(a.outStream, "failures allowed by -allow-fail: sample %s\n", strings.Join(numbers, ", "))
+}
+
+// allowedNote returns a note like ' (1 allowed failure)' to append to the verdict, or an empty string.
+func allowedNote(allowed int) string {
+	switch allowed {
+	case 0:
+		return ""
+	case 1:
+		return " (1 allowed failure)"
+	default:
+		return fmt.Sprintf(" (%d allowed failures)", allowed)
+	}
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSampleNumbers(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expected       map[int]bool
+		expectedErrMsg string
+	}{
+		{
+			name:     "success-empty",
+			input:    "",
+			expected: map[int]bool{},
+		},
+		{
+			name:     "success-numbers",
+			input:    "2, 4,",
+			expected: map[int]bool{2: true, 4: true},
+		},
+		{
+			name:           "failure-not a number",
+			input:          "2,x",
+			expectedErrMsg: "invalid sample number 'x'",
+		},
+		{
+			name:           "failure-zero",
+			input:          "0",
+			expectedErrMsg: "invalid sample number '0'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := parseSampleNumbers(test.input)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Fatalf("numbers wrong. want=%v, got=%v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestApp_Run_allowFail(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputArgs      []string
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name:           "allowed",
+			inputArgs:      []string{"atctest", "-allow-fail", "1", "-input", "1", "-expected", "2", "-command", "cat"},
+			expectedOutput: "sample 1: FAILURE",
+		},
+		{
+			name:           "allowed-noted separately",
+			inputArgs:      []string{"atctest", "-allow-fail", "1", "-input", "1", "-expected", "2", "-command", "cat"},
+			expectedOutput: "failures allowed by -allow-fail: sample 1\n",
+		},
+		{
+			name:           "allowed-oneline",
+			inputArgs:      []string{"atctest", "-format", "oneline", "-allow-fail", "1", "-input", "1", "-expected", "2", "-command", "cat"},
+			expectedOutput: "inline: 0/1 PASS (1 allowed failure)\n",
+		},
+		{
+			name:           "not allowed",
+			inputArgs:      []string{"atctest", "-allow-fail", "2", "-input", "1", "-expected", "2", "-command", "cat"},
+			expectedOutput: "sample 1: FAILURE",
+			expectedErr:    ErrSamplesFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setupHome(t)
+
+			var outStream, errStream bytes.Buffer
+			a, err := New(test.inputArgs, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if err := a.Run(); err != test.expectedErr {
+				t.Fatalf("err wrong. want=%v, got=%v", test.expectedErr, err)
+			}
+			if !strings.Contains(outStream.String(), test.expectedOutput) {
+				t.Fatalf("expect '%s' to contain '%s'", outStream.String(), test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestApp_Run_allowFailSkipUnchanged(t *testing.T) {
+	setupHome(t)
+
+	args := []string{"atctest", "-skip-unchanged", "-allow-fail", "1", "-input", "1", "-expected", "2", "-command", "cat"}
+	for i, expected := range []string{"sample 1: FAILURE", "unchanged since the last run: SUCCESS"} {
+		var outStream, errStream bytes.Buffer
+		a, err := New(args, &outStream, &errStream)
+		if err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+		if err := a.Run(); err != nil {
+			t.Fatalf("err of run %d should be nil. got: %s", i+1, err)
+		}
+		if !strings.Contains(outStream.String(), expected) {
+			t.Fatalf("expect '%s' to contain '%s'", outStream.String(), expected)
+		}
+	}
+}
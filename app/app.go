@@ -2,35 +2,83 @@ package app
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
 	"path"
+	"regexp"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/mitchellh/go-homedir"
 	"github.com/mui87/atctest/atcoder"
+	"github.com/mui87/atctest/browser"
+	"github.com/mui87/atctest/clipboard"
+	"github.com/mui87/atctest/commander"
 )
 
 const baseURL = "https://atcoder.jp"
 
+// ErrSamplesFailed is returned by Run when the program does not pass all the samples.
+// the details have already been reported to outStream.
+var ErrSamplesFailed = errors.New("some samples failed")
+
 type App struct {
-	client  *atcoder.Client
-	checker *atcoder.Checker
+	client      *atcoder.Client
+	checker     *atcoder.Checker
+	resultCache *resultCache
 
-	contest string
-	problem string
-	command string
+	contest  string
+	problem  string
+	problems []string
+	command  string
+	// buildCommand is run once before the samples. see build.
+	buildCommand string
 
-	username string
-	password string
+	username      string
+	password      string
+	cookieJarPath string
 
 	contestURL string
 	problemURL string
 
+	cacheDirPath string
+
+	format       string
+	summary      bool
+	examples     bool
+	version      bool
+	listProblems bool
+	doctor       bool
+	commandLine  string
+
+	inlineSample  *atcoder.Sample
+	stdinSamples  bool
+	htmlFilePath  string
+	samplesDir    string
+	dumpDir       string
+	downloadOnly  bool
+	showSamples   bool
+	skipUnchanged bool
+	rerunFailed   bool
+	openBrowser   bool
+	copyResult    bool
+	snapshotPath  string
+	deadline      time.Time
+	allowFail     map[int]bool
+	only          map[int]bool
+
+	inStream  io.Reader
 	outStream io.Writer
 	errStream io.Writer
+	teeFile   *os.File
 }
 
 func New(args []string, outStream, errStream io.Writer) (*App, error) {
@@ -44,26 +92,280 @@ func New(args []string, outStream, errStream io.Writer) (*App, error) {
 	}
 
 	var (
-		contest    string
-		problem    string
-		command    string
-		username   string
-		password   string
-		problemURL string
-		nocache    bool
+		contest       string
+		problem       string
+		command       string
+		buildCommand  string
+		username      string
+		password      string
+		cookie        string
+		cookieJarPath string
+		configPath    string
+		envFilePath   string
+		teePath       string
+		noColor       bool
+		snapshotPath  string
+		allowFail     string
+		only          string
+		loginRetries  int
+		retries       int
+		httpTimeout   time.Duration
+		proxy         string
+		cacheDir      string
+		lang          string
+		verbose       bool
+		relogin       bool
+		maxRetryTime  time.Duration
+		requestGap    time.Duration
+		deadline      time.Duration
+		problemURL    string
+		problemList   string
+		format        string
+		jsonFormat    bool
+		summary       bool
+		examples      bool
+		showVersion   bool
+		listProblems  bool
+		doctor        bool
+		printCommand  bool
+		input         string
+		expected      string
+		stdinSamples  bool
+		htmlFilePath  string
+		samplesDir    string
+		dumpDir       string
+		downloadOnly  bool
+		showSamples   bool
+		nocache       bool
+		refresh       bool
+		cacheTTL      time.Duration
+		readOnlyCache bool
+		usePTY        bool
+		memoryLimit   int
+		encodingName  string
+		skipUnchanged bool
+		rerunFailed   bool
+		openBrowser   bool
+		copyResult    bool
+		check         bool
+		requireNL     bool
+		forbidNL      bool
+		round         int
+		compare       string
+		inputMode     string
+		ignoreLines   string
+		opts          atcoder.Options
 	)
 	flags.StringVar(&contest, "contest", "", "contest you are challenging. e.g.) ABC051")
 	flags.StringVar(&problem, "problem", "", "problem you are solving. e.g.) C")
 	flags.StringVar(&command, "command", "", "command to execute your program. e.g.) 'python c.py'")
+	flags.StringVar(&buildCommand, "build", "", "command run once before the samples, e.g. to compile your program, instead of in -command for each sample. e.g.) 'g++ -O2 c.cpp'")
 	flags.StringVar(&username, "username", "", "your username of atcoder account. e.g.) 'chokudai'")
-	flags.StringVar(&password, "password", "", "your password of atcoder account. e.g.) 'password'")
+	flags.StringVar(&password, "password", "", "your password of atcoder account. $"+envPassword+" is safer, since the options are left in your shell history. e.g.) 'password'")
+	flags.IntVar(&retries, "retries", 3, "how many times a page is fetched again after network errors or 5xx, waiting 1s, 2s, 4s, ... in between. 404 is never retried.")
+	flags.DurationVar(&httpTimeout, "http-timeout", atcoder.DefaultHTTPTimeout, "duration after which each request to AtCoder is given up, e.g. on a stalled connection. 0 means no limit. e.g.) 10s")
+	flags.StringVar(&proxy, "proxy", "", "url of the proxy used instead of the one given by HTTP_PROXY, HTTPS_PROXY and NO_PROXY. e.g.) 'http://proxy.example.com:8080'")
+	flags.IntVar(&loginRetries, "login-retries", 2, "how many times login is retried after transient failures. wrong username/password is never retried.")
+	flags.DurationVar(&maxRetryTime, "max-total-retry-time", 0, "time atctest keeps retrying across all network operations before giving up. 0 means no limit. e.g.) 60s")
+	flags.DurationVar(&requestGap, "request-gap", 0, "minimum time between the last request to AtCoder of the previous run and the first one of this run, to avoid bursts of requests. 0 disables it. e.g.) 3s")
+	flags.StringVar(&configPath, "config", "", "path of the config file used instead of ~/.atctest/"+configFileName+". e.g.) ./atctest.json")
+	flags.StringVar(&envFilePath, "env-file", "", "path of the file of KEY=VALUE lines loaded into the environment. the variables already set take precedence. e.g.) .env")
+	flags.StringVar(&cookie, "cookie", "", "cookies copied from your browser, used to pass the challenge page. e.g.) 'REVEL_SESSION=xxx; cf_clearance=yyy'")
+	flags.StringVar(&cookieJarPath, "cookie-jar", "", "path of the file the session is loaded from and saved to, e.g. for each of your accounts. e.g.) ~/.atctest/alice.jar (default ~/.atctest/"+sessionFileName+")")
+	flags.BoolVar(&relogin, "relogin", false, "if set, the saved session is ignored and login is done again.")
 	flags.StringVar(&problemURL, "url", "", "url of the problem page. e.g.) 'https://abc051.contest.atcoder.jp/tasks/abc051_c'")
+	flags.StringVar(&lang, "lang", "auto", "language of the sample headings taken from the problem page. 'ja', 'en' or 'auto', which takes Japanese ones if any.")
+	flags.StringVar(&problemList, "problem-list-url", "", "[advanced] pattern of the url of the problem list page, where {contest} is replaced with the contest. it overrides problem_list_url of the config file. e.g.) '"+atcoder.DefaultProblemListURLPattern+"'")
+	flags.StringVar(&teePath, "tee", "", "path of the file which the output is also written to, without colors. e.g.) practice.log")
+	flags.BoolVar(&noColor, "no-color", false, "if set, the output is not colored. it is also not colored when $"+envNoColor+" is set or the output is not a terminal.")
+	flags.StringVar(&only, "only", "", "comma-separated numbers of the samples to run instead of all of them. e.g.) 2,4")
+	flags.StringVar(&allowFail, "allow-fail", "", "comma-separated numbers of the samples whose failures do not fail the run. they are still shown. e.g.) 2,4")
+	flags.StringVar(&snapshotPath, "snapshot", "", "path of the file the outputs of your program are recorded to on the first run and compared with on later runs, regardless of the expected outputs. e.g.) c.snapshot.json")
+	flags.StringVar(&format, "format", formatText, "format of the results. 'text', 'oneline' or 'json'. 'oneline' prints a single line like 'ABC051/C: 4/6 PASS'.")
+	flags.BoolVar(&jsonFormat, "json", false, "if set, the results are printed as a JSON document, the same as -format json.")
+	flags.BoolVar(&showVersion, "version", false, "if set, the version of atctest is printed.")
+	flags.BoolVar(&summary, "summary", false, "if set, the latest result of each problem recorded in the file of $"+envState+" is printed.")
+	flags.BoolVar(&listProblems, "list-problems", false, "if set, the problems of the contest given by -contest are listed.")
+	flags.BoolVar(&doctor, "doctor", false, "if set, the environment is checked and the result of each check is printed.")
+	flags.BoolVar(&printCommand, "print-command", false, "if set, the command line of atctest for the other options is printed without running it, with the secrets left out.")
+	flags.BoolVar(&examples, "examples", false, "if set, example commands for common languages are printed with the contest and problem given by the other options.")
+	flags.StringVar(&input, "input", "", "input of a sample given inline instead of the samples on the problem page. escapes like \\n are interpreted. e.g.) '1 2'")
+	flags.StringVar(&expected, "expected", "", "expected output of the sample given by -input. e.g.) '3'")
+	flags.BoolVar(&stdinSamples, "stdin-samples", false, "if set, samples are read from stdin instead of the problem page. each sample is a line of '"+stdinInputDelimiter+"', the input, a line of '"+stdinOutputDelimiter+"' and the output.")
+	flags.StringVar(&samplesDir, "samplesdir", "", "directory of the pairs of files like 1.in and 1.out to read the samples from instead of AtCoder. e.g.) ./cases")
+	flags.StringVar(&dumpDir, "dump", "", "directory to write the samples to as the pairs of files like 1.in and 1.out, in the format of -samplesdir. e.g.) ./cases")
+	flags.BoolVar(&downloadOnly, "download-only", false, "if set, the samples are only downloaded to the cache, and written with -dump, without running your program. -command is not required.")
+	flags.BoolVar(&showSamples, "show-samples", false, "if set, the input and expected output of each sample are printed without running your program, to check the samples are taken correctly. -command is not required.")
+	flags.StringVar(&htmlFilePath, "html-file", "", "path of the problem page saved by your browser, as HTML or MHTML, to read the samples from instead of AtCoder. e.g.) abc051_c.mhtml")
 	flags.BoolVar(&nocache, "nocache", false, "if set, local cache of samples is not used.")
+	flags.BoolVar(&refresh, "refresh", false, "if set, the samples are fetched again and the cache is updated with them, e.g. after a sample is fixed on AtCoder. the same as -nocache.")
+	flags.DurationVar(&cacheTTL, "cache-ttl", 0, "age after which the cached samples are fetched again. 0 means they never expire. e.g.) 24h")
+	flags.StringVar(&cacheDir, "cachedir", "", "directory of the cache, e.g. a project-local one. it overrides "+envCacheDir+". (default ~/.atctest)")
+	flags.BoolVar(&verbose, "verbose", false, "if set, the urls visited and whether the cache is hit are printed to stderr.")
+	flags.BoolVar(&readOnlyCache, "read-only-cache", false, "if set, local cache is used but never written.")
+	flags.BoolVar(&skipUnchanged, "skip-unchanged", false, "if set, the result of the last run is reported without running your program when neither the samples, the command nor the files in the command have changed.")
+	flags.BoolVar(&rerunFailed, "rerun-failed", false, "if set, only the samples which failed in the last run with the same command are run.")
+	flags.BoolVar(&openBrowser, "open", false, "if set, the problem page is opened with your browser.")
+	flags.BoolVar(&usePTY, "pty", false, "[advanced] if set, your program runs with its stdout attached to a pseudo-terminal, for programs which flush differently on a terminal.")
+	flags.StringVar(&encodingName, "encoding", "utf-8", "[advanced] encoding your program reads and writes in. the input is converted to it and the output is converted from it. e.g.) shift_jis")
+	flags.BoolVar(&check, "check", false, "if set, nothing is printed except errors. the exit status tells whether all the samples passed, for git hooks and scripts.")
+	flags.BoolVar(&copyResult, "copy-result", false, "if set, a summary of the results is copied to the clipboard.")
+	flags.StringVar(&opts.CommandPrefix, "command-prefix", "", "[advanced] prefix prepended to the command when it is executed. e.g.) 'taskset -c 0'")
+	flags.StringVar(&inputMode, "input-mode", "stdin", "how the input of each sample is given to your program. 'stdin' or 'file'. 'file' writes it to a temporary file and replaces "+atcoder.InputPlaceholder+" in the command with its path. e.g.) -input-mode file -command 'python c.py "+atcoder.InputPlaceholder+"'")
+	flags.StringVar(&opts.InputTransform, "input-transform", "", "[advanced] command which the input of each sample is piped through before it is given to your program. e.g.) 'base64 -d'")
+	flags.BoolVar(&opts.ShowDiff, "diff", false, "if set, the differing lines of the expected and actual output are shown in red and green with a few lines of context, instead of both of them in full.")
+	flags.BoolVar(&opts.HexDump, "hexdump", false, "if set, the bytes around the first difference of the expected and actual output are also shown side by side in hex, to find invisible characters.")
+	flags.IntVar(&opts.DiffMaxLines, "diff-max-lines", 20, "number of differing lines shown by -diff before the rest is omitted. 0 means no limit.")
+	flags.BoolVar(&opts.StripANSI, "strip-ansi", false, "if set, ANSI escape sequences in the output of your program are removed before comparison.")
+	flags.StringVar(&ignoreLines, "ignore-lines", "", "regular expression of the lines removed from the output of your program before comparison. e.g.) '^DEBUG:'")
+	flags.BoolVar(&opts.NumericInteger, "numeric-int", false, "if set, integer tokens are compared by their values, ignoring leading zeros and plus signs. e.g.) '007' matches '7'")
+	flags.BoolVar(&opts.SortLineTokens, "sort-line-tokens", false, "if set, the tokens in each line are sorted before comparison, for problems accepting them in any order.")
+	flags.StringVar(&opts.Checker, "checker", "", "command which decides whether the output of your program is accepted, for problems with multiple valid outputs. it is run with the paths of the files of the input, the expected output and the actual output, and exits with 0 to accept or 1 to reject. e.g.) './judge'")
+	flags.StringVar(&compare, "compare", "text", "how the outputs are compared. 'text' or 'json'. 'json' ignores whitespace and the order of the keys of objects.")
+	flags.Float64Var(&opts.Tolerance, "tolerance", 0, "if set, numeric tokens are accepted when either the absolute or the relative error is within it. e.g.) 1e-6")
+	flags.IntVar(&round, "round", -1, "if set, numeric tokens are rounded to the number of digits after the decimal point before comparison. e.g.) 6")
+	flags.BoolVar(&opts.Strict, "strict", false, "if set, the output of your program is compared byte by byte, without ignoring CRLF line endings, trailing whitespace of each line and trailing blank lines.")
+	flags.BoolVar(&requireNL, "require-trailing-newline", false, "if set, the output of your program must end with a newline.")
+	flags.BoolVar(&forbidNL, "require-no-trailing-newline", false, "if set, the output of your program must not end with a newline.")
+	flags.IntVar(&opts.ExitCode, "exit-code", 0, "exit code your program is expected to exit with. the output is compared only when it exits with it. e.g.) 1")
+	flags.BoolVar(&opts.RuntimeError, "runtime-error", false, "if set, the samples your program exits with another code than -exit-code are reported as RUNTIME ERROR, like RE of AtCoder, instead of ERROR.")
+	flags.IntVar(&memoryLimit, "memory-limit", 0, "peak memory usage in MB over which the samples are reported as MEMORY LIMIT EXCEEDED. 0 means no limit. e.g.) 1024")
+	flags.DurationVar(&opts.Timeout, "timeout", 0, "duration after which your program is killed for each sample. 0 means no limit. e.g.) 2s")
+	flags.DurationVar(&deadline, "deadline", 0, "duration after which the whole run including login, fetching and all the samples is given up. 0 means no limit. e.g.) 1m")
+	flags.DurationVar(&opts.WarnSlow, "warn-slow", 0, "duration over which passing samples are marked as slow. e.g.) 1s")
+	flags.BoolVar(&opts.FailFast, "failfast", false, "if set, the samples after the first one which does not pass are not run.")
+	flags.IntVar(&opts.Jobs, "jobs", 1, "number of samples run concurrently. the results are still printed in the order of the samples. e.g.) 4")
+	flags.DurationVar(&opts.DelayBetweenSamples, "delay-between-samples", 0, "duration to wait between the executions of samples. e.g.) 500ms")
 	if err := flags.Parse(args[1:]); err != nil {
 		return nil, errors.New("failed to parse flags")
 	}
+	if showVersion {
+		// nothing else is needed, not even the config file
+		return &App{outStream: outStream, errStream: errStream, version: true}, nil
+	}
+	// the letter may be given as ' c ' by a shell script
+	problem = strings.TrimSpace(problem)
+	problems := parseProblems(problem)
+	if len(problems) > 1 {
+		if problemURL != "" || input != "" || stdinSamples || htmlFilePath != "" || samplesDir != "" {
+			return nil, errors.New("multiple problems can be tested only with -contest")
+		}
+		if dumpDir != "" || snapshotPath != "" {
+			return nil, errors.New("-dump and -snapshot cannot be used with multiple problems")
+		}
+		if jsonFormat || format == formatJSON {
+			return nil, errors.New("the results of multiple problems cannot be printed as JSON")
+		}
+	} else {
+		command = commandFor(command, problem)
+		buildCommand = commandFor(buildCommand, problem)
+	}
 
-	if problemURL == "" {
+	if deadline > 0 {
+		opts.Deadline = time.Now().Add(deadline)
+	}
+
+	if envFilePath != "" {
+		if err := loadEnvFile(envFilePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if jsonFormat {
+		format = formatJSON
+	}
+	if format != formatText && format != formatOneline && format != formatJSON {
+		return nil, fmt.Errorf("unknown format '%s'. specify 'text', 'oneline' or 'json'", format)
+	}
+
+	sampleLang, err := atcoder.ParseLang(lang)
+	if err != nil {
+		return nil, err
+	}
+	compareMode, err := atcoder.ParseCompareMode(compare)
+	if err != nil {
+		return nil, err
+	}
+	opts.Compare = compareMode
+	if opts.InputMode, err = atcoder.ParseInputMode(inputMode); err != nil {
+		return nil, err
+	}
+	if opts.InputMode == atcoder.InputModeFile && command != "" && !strings.Contains(command, atcoder.InputPlaceholder) {
+		return nil, fmt.Errorf("-command must contain %s to be replaced with the path of the input with -input-mode file", atcoder.InputPlaceholder)
+	}
+	if opts.Jobs < 1 {
+		return nil, fmt.Errorf("-jobs must be 1 or more. got: %d", opts.Jobs)
+	}
+	if opts.ExitCode < 0 || opts.ExitCode > 255 {
+		return nil, fmt.Errorf("-exit-code must be between 0 and 255. got: %d", opts.ExitCode)
+	}
+	if memoryLimit < 0 {
+		return nil, errors.New("-memory-limit must not be negative")
+	}
+	opts.MemoryLimit = int64(memoryLimit) << 20
+	if opts.Tolerance < 0 {
+		return nil, fmt.Errorf("-tolerance must not be negative. got: %g", opts.Tolerance)
+	}
+	if ignoreLines != "" {
+		if opts.IgnoreLines, err = regexp.Compile(ignoreLines); err != nil {
+			return nil, fmt.Errorf("-ignore-lines is not a valid regular expression: %s", err)
+		}
+	}
+	allowFailNumbers, err := parseSampleNumbers(allowFail)
+	if err != nil {
+		return nil, err
+	}
+	onlyNumbers, err := parseSampleNumbers(only)
+	if err != nil {
+		return nil, err
+	}
+
+	if round >= 0 {
+		opts.Round = true
+		opts.RoundDigits = round
+	}
+
+	switch {
+	case requireNL && forbidNL:
+		return nil, errors.New("-require-trailing-newline and -require-no-trailing-newline cannot be used together")
+	case requireNL:
+		opts.TrailingNewline = atcoder.TrailingNewlineRequired
+	case forbidNL:
+		opts.TrailingNewline = atcoder.TrailingNewlineForbidden
+	}
+
+	var inlineSample *atcoder.Sample
+	switch {
+	case summary:
+		// the summary is made only from the state file
+	case examples:
+		// the examples are filled with whatever is given
+	case doctor:
+		// the options given are checked if any
+	case listProblems:
+		if contest == "" {
+			flags.Usage()
+			return nil, errors.New("specify the contest to list the problems of. e.g.) ABC051")
+		}
+	case stdinSamples, htmlFilePath != "", samplesDir != "":
+		if command == "" && !downloadOnly && !showSamples {
+			flags.Usage()
+			return nil, errors.New("specify the command to execute your program. e.g.) 'python c.py'")
+		}
+	case input != "" || expected != "":
+		if input == "" || expected == "" {
+			flags.Usage()
+			return nil, errors.New("specify both -input and -expected to test with an inline sample")
+		}
+		if command == "" {
+			flags.Usage()
+			return nil, errors.New("specify the command to execute your program. e.g.) 'python c.py'")
+		}
+		inlineSample = &atcoder.Sample{
+			Input:  unescapeInline(input),
+			Output: unescapeInline(expected),
+		}
+	case problemURL == "":
 		if contest == "" {
 			flags.Usage()
 			return nil, fmt.Errorf("specify the contest you are challenging. e.g.) ABC051\n\n%s", errBuff.String())
@@ -72,7 +374,7 @@ func New(args []string, outStream, errStream io.Writer) (*App, error) {
 			flags.Usage()
 			return nil, errors.New("specify the problem you are solving. e.g.) C")
 		}
-		if command == "" {
+		if command == "" && !downloadOnly && !showSamples {
 			flags.Usage()
 			return nil, errors.New("specify the command to execute your program. e.g.) 'python c.py'")
 		}
@@ -84,58 +386,425 @@ func New(args []string, outStream, errStream io.Writer) (*App, error) {
 	if problemURL == "" {
 		contestURL = fmt.Sprintf("%s/contests/%s", baseURL, strings.ToLower(contest))
 	} else {
-		contestURL = strings.TrimRight(problemURL, "/")
-		i := strings.LastIndex(contestURL, "/")
-		contestURL = contestURL[:i]
-		i = strings.LastIndex(contestURL, "/")
-		contestURL = contestURL[:i]
+		var err error
+		if contestURL, err = contestURLOf(problemURL); err != nil {
+			return nil, err
+		}
 	}
 
-	useCache := !nocache
-	var cacheDirPath string
-	home, err := homedir.Dir()
+	if cacheTTL < 0 {
+		return nil, errors.New("-cache-ttl must not be negative")
+	}
+	useCache := !nocache && !refresh
+	cacheDirPath, explicitCacheDir, err := resolveCacheDir(cacheDir)
 	if err != nil {
-		cacheDirPath = ""
-	} else {
-		cacheDirPath = path.Join(home, ".atctest")
+		return nil, err
 	}
-	client := atcoder.NewClient(baseURL, useCache, cacheDirPath, outStream, errStream)
+	if explicitCacheDir && !readOnlyCache {
+		// fail early rather than on writing the cache after running the samples
+		if err := checkWritable(cacheDirPath); err != nil {
+			return nil, fmt.Errorf("cache directory cannot be used: %s", err)
+		}
+	}
+
+	configFilePath := configPath
+	if home, err := homedir.Dir(); configFilePath == "" && err == nil {
+		// the config file stays in the home directory even if the cache directory is moved
+		configFilePath = path.Join(home, ".atctest", configFileName)
+	}
+	cfg, err := loadConfig(configFilePath, configPath != "")
 	if err != nil {
 		return nil, err
 	}
+	warnReadableConfig(configFilePath, cfg, errStream)
+	username = resolveCredential(username, envUsername, cfg.Username)
+	password = resolveCredential(password, envPassword, cfg.Password)
+
+	if loginRetries < 0 {
+		return nil, errors.New("-login-retries must not be negative")
+	}
+	if retries < 0 {
+		return nil, errors.New("-retries must not be negative")
+	}
+	if httpTimeout < 0 {
+		return nil, errors.New("-http-timeout must not be negative")
+	}
+
+	client := atcoder.NewClient(baseURL, useCache, cacheDirPath, outStream, errStream)
+	client.SetLoginRetries(loginRetries)
+	client.SetRetries(retries)
+	client.SetHTTPTimeout(httpTimeout)
+	if proxy != "" {
+		if err := client.SetProxy(proxy); err != nil {
+			return nil, err
+		}
+	}
+	client.SetLang(sampleLang)
+	client.SetVerbose(verbose)
+	client.SetMaxTotalRetryTime(maxRetryTime)
+	client.SetReadOnlyCache(readOnlyCache)
+	client.SetCacheTTL(cacheTTL)
+	client.SetMinRequestGap(requestGap)
+	client.SetDeadline(opts.Deadline)
+	if problemList == "" {
+		problemList = cfg.ProblemListURL
+	}
+	if problemList != "" {
+		if err := client.SetProblemListURLPattern(problemList); err != nil {
+			return nil, err
+		}
+	}
+	var jarPath string
+	if cookieJarPath != "" {
+		if cookieJarPath, err = homedir.Expand(cookieJarPath); err != nil {
+			return nil, err
+		}
+		jarPath = cookieJarPath
+	} else if cacheDirPath != "" {
+		jarPath = path.Join(cacheDirPath, sessionFileName)
+	}
+	if jarPath != "" && !relogin {
+		if err := client.LoadCookieJar(jarPath); err != nil {
+			return nil, err
+		}
+	}
+	if cookieJarPath == "" && readOnlyCache {
+		// the session in the cache directory is used but never written
+		jarPath = ""
+	}
+	if cookie != "" {
+		if err := client.SetCookies(cookie); err != nil {
+			return nil, err
+		}
+	}
+
+	var line string
+	if printCommand {
+		line = commandLine(flags, map[string]string{"url": problemURL, "cookie-jar": cookieJarPath})
+	}
+
+	if check {
+		outStream = ioutil.Discard
+	}
 
-	checker := atcoder.NewChecker(outStream, errStream)
+	color.NoColor = !useColor(noColor, outStream)
+
+	var teeFile *os.File
+	if teePath != "" {
+		teeFile, err = os.Create(teePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the file for -tee: %s", err)
+		}
+		outStream = io.MultiWriter(outStream, &ansiStripWriter{w: teeFile})
+	}
+
+	checker := atcoder.NewChecker(opts, outStream, errStream)
+	var cmd commander.Commander = commander.NewExternal()
+	if usePTY {
+		p, err := commander.NewPTY()
+		if err != nil {
+			return nil, fmt.Errorf("-pty cannot be used: %s", err)
+		}
+		cmd = p
+	}
+	if !commander.IsUTF8(encodingName) {
+		t, err := commander.NewTranscoding(cmd, encodingName)
+		if err != nil {
+			return nil, err
+		}
+		cmd = t
+	}
+	checker.SetCommander(cmd)
+	if memoryLimit > 0 && (!commander.MemoryMeasured || usePTY) {
+		_, _ = fmt.Fprintln(errStream, "[WARNING] the memory usage is not measured on this platform or with -pty. -memory-limit is ignored")
+	}
 
 	return &App{
-		client:  client,
-		checker: checker,
+		client:      client,
+		checker:     checker,
+		resultCache: newResultCache(cacheDirPath, readOnlyCache, opts),
 
-		contest: contest,
-		problem: problem,
-		command: command,
+		contest:      contest,
+		problem:      problem,
+		problems:     problems,
+		command:      command,
+		buildCommand: buildCommand,
 
-		username: username,
-		password: password,
+		username:      username,
+		password:      password,
+		cookieJarPath: jarPath,
 
 		contestURL: contestURL,
 		problemURL: problemURL,
 
+		cacheDirPath: cacheDirPath,
+
+		format:       format,
+		summary:      summary,
+		examples:     examples,
+		listProblems: listProblems,
+		doctor:       doctor,
+		commandLine:  line,
+
+		inlineSample:  inlineSample,
+		stdinSamples:  stdinSamples,
+		htmlFilePath:  htmlFilePath,
+		samplesDir:    samplesDir,
+		dumpDir:       dumpDir,
+		downloadOnly:  downloadOnly,
+		showSamples:   showSamples,
+		skipUnchanged: skipUnchanged,
+		rerunFailed:   rerunFailed,
+		openBrowser:   openBrowser,
+		copyResult:    copyResult,
+		snapshotPath:  snapshotPath,
+		deadline:      opts.Deadline,
+		allowFail:     allowFailNumbers,
+		only:          onlyNumbers,
+
+		inStream:  os.Stdin,
 		outStream: outStream,
 		errStream: errStream,
+		teeFile:   teeFile,
 	}, nil
 }
 
+// Close closes the file of -tee if any.
+func (a *App) Close() error {
+	if a.teeFile == nil {
+		return nil
+	}
+	return a.teeFile.Close()
+}
+
 func (a *App) Run() error {
-	beingHeld, err := a.client.IsContestBeingHeld(a.contestURL)
+	if a.version {
+		_, _ = fmt.Fprintln(a.outStream, versionString())
+		return nil
+	}
+	if a.commandLine != "" {
+		_, _ = fmt.Fprintln(a.outStream, a.commandLine)
+		return nil
+	}
+	if a.summary {
+		return a.printSummary()
+	}
+	if a.examples {
+		a.printExamples()
+		return nil
+	}
+	if a.listProblems {
+		return a.printProblems()
+	}
+	if a.doctor {
+		return a.runDoctor()
+	}
+	if len(a.problems) > 1 {
+		return a.runProblems()
+	}
+	return a.runProblem()
+}
+
+// runProblem runs the samples of the problem, or of the samples given in another way.
+func (a *App) runProblem() error {
+	problemKey, samples, err := a.getSamples()
+	if errors.Is(err, atcoder.ErrNoSamples) {
+		// nothing to test, e.g.) interactive problems. it is not a failure of your program
+		_, _ = fmt.Fprintln(a.outStream, "this problem has no downloadable samples")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	if beingHeld {
-		if err := a.client.LogIn(a.username, a.password); err != nil {
+	if a.dumpDir != "" {
+		if err := writeSamplesDir(a.dumpDir, samples); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(a.errStream, "%d samples are written to %s\n", len(samples), a.dumpDir)
+	}
+	if a.downloadOnly {
+		_, _ = fmt.Fprintf(a.outStream, "%d samples are downloaded\n", len(samples))
+		return nil
+	}
+	if len(a.only) > 0 {
+		if samples, err = selectSamples(samples, a.only); err != nil {
+			return err
+		}
+	}
+	if a.showSamples {
+		printSamples(a.outStream, samples)
+		return nil
+	}
+
+	if a.skipUnchanged {
+		if success, ok := a.resultCache.lookup(problemKey, a.resultKey(), samples, a.allowFail); ok {
+			if err := a.printCachedResult(problemKey, samples, success); err != nil {
+				return err
+			}
+			if !success {
+				return ErrSamplesFailed
+			}
+			return nil
+		}
+	}
+
+	if err := a.build(); err != nil {
+		return err
+	}
+
+	targets := samples
+	if a.rerunFailed {
+		targets = a.failedSamples(problemKey, samples)
+	}
+
+	var results []atcoder.Result
+	switch a.format {
+	case formatOneline:
+		results = a.checker.CheckResults(a.command, targets)
+		a.printOneline(problemKey, results)
+	case formatJSON:
+		results = a.checker.CheckResults(a.command, targets)
+		if err := a.printJSON(problemKey, results); err != nil {
+			return err
+		}
+	default:
+		results = a.checker.CheckAndRender(a.command, targets)
+		a.printAllowedFailures(results)
+	}
+	if !a.deadline.IsZero() && time.Now().After(a.deadline) {
+		return atcoder.ErrDeadlineExceeded
+	}
+
+	if a.copyResult {
+		if err := clipboard.Copy(a.resultSummary(problemKey, results)); err != nil {
+			_, _ = fmt.Fprintln(a.errStream, "failed to copy the result: "+err.Error())
+		}
+	}
+	if err := a.resultCache.store(problemKey, a.resultKey(), samples, results); err != nil {
+		_, _ = fmt.Fprintln(a.errStream, "failed to cache the result: "+err.Error())
+	}
+	if stateFilePath := os.Getenv(envState); stateFilePath != "" {
+		failed, _ := a.countFailures(results)
+		if err := appendState(stateFilePath, a.problemLabel(problemKey), a.command, results, failed == 0); err != nil {
+			_, _ = fmt.Fprintln(a.errStream, "failed to record the result to the state file: "+err.Error())
+		}
+	}
+	if a.snapshotPath != "" {
+		if err := a.checkSnapshot(results); err != nil {
 			return err
-		} else {
-			fmt.Println("login success")
+		}
+	}
+	if failed, _ := a.countFailures(results); failed > 0 {
+		return ErrSamplesFailed
+	}
+
+	return nil
+}
+
+// printProblems prints the letter, title and URL of each problem of the contest in a table.
+func (a *App) printProblems() error {
+	problems, err := a.client.ListProblems(a.contest)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(a.outStream, 0, 0, 2, ' ', 0)
+	for _, problem := range problems {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", problem.Letter, problem.Title, problem.URL)
+	}
+	return w.Flush()
+}
+
+// failedSamples returns the samples which failed in the last run, or all the samples if there are none.
+func (a *App) failedSamples(problemKey string, samples []atcoder.Sample) []atcoder.Sample {
+	numbers, ok := a.resultCache.failedNumbers(problemKey, a.resultKey(), samples)
+	if !ok || len(numbers) == 0 {
+		_, _ = fmt.Fprintln(a.errStream, "no failed samples in the last run. running all the samples.")
+		return samples
+	}
+
+	failed := make(map[int]bool)
+	for _, n := range numbers {
+		failed[n] = true
+	}
+
+	var targets []atcoder.Sample
+	for i, sample := range samples {
+		if sample.Number == 0 {
+			sample.Number = i + 1
+		}
+		if failed[sample.Number] {
+			targets = append(targets, sample)
+		}
+	}
+	return targets
+}
+
+// logIn is replaced in tests to see the credentials passed to it.
+var logIn = (*atcoder.Client).LogIn
+
+// logIn logs in with the credentials resolved by New from the flags, the environment variables and the config file,
+// asking for the password if only the username is given. the session is saved to the cookie jar if any.
+func (a *App) logIn() error {
+	if !a.client.IsLoggedIn(a.username) {
+		if err := a.promptPassword(); err != nil {
+			return err
+		}
+	}
+	if err := logIn(a.client, a.username, a.password); err != nil {
+		return err
+	} else if a.format == formatText {
+		_, _ = fmt.Fprintln(a.outStream, "login success")
+	}
+	if a.cookieJarPath != "" {
+		if err := a.client.SaveCookieJar(a.cookieJarPath); err != nil {
+			_, _ = fmt.Fprintln(a.errStream, "failed to save the cookie jar: "+err.Error())
+		}
+	}
+	return nil
+}
+
+// getSamples returns the samples to test with and the key identifying the problem they belong to.
+func (a *App) getSamples() (string, []atcoder.Sample, error) {
+	if a.inlineSample != nil {
+		return "inline", []atcoder.Sample{*a.inlineSample}, nil
+	}
+	if a.stdinSamples {
+		samples, err := parseStdinSamples(a.inStream)
+		if err != nil {
+			return "", nil, err
+		}
+		return "stdin", samples, nil
+	}
+	if a.htmlFilePath != "" {
+		samples, err := a.client.GetSamplesFromFile(a.htmlFilePath)
+		if err != nil {
+			return "", nil, err
+		}
+		return a.htmlFilePath, samples, nil
+	}
+	if a.samplesDir != "" {
+		samples, err := readSamplesDir(a.samplesDir)
+		if err != nil {
+			return "", nil, err
+		}
+		return a.samplesDir, samples, nil
+	}
+
+	// Ctrl-C cancels the requests in flight. it stops atctest as usual again once the samples are fetched,
+	// so that it is not swallowed while your program runs.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	beingHeld, err := a.client.IsContestBeingHeldContext(ctx, a.contestURL)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if beingHeld {
+		if err := a.logIn(); err != nil {
+			return "", nil, err
 		}
 	}
 
@@ -144,22 +813,35 @@ func (a *App) Run() error {
 		problemURL = a.problemURL
 	} else {
 		var err error
-		problemURL, err = a.client.GetProblemURL(a.contest, a.problem)
+		problemURL, err = a.client.GetProblemURLContext(ctx, a.contest, a.problem)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 	}
 
-	samples, err := a.client.GetSamples(problemURL)
-	if err != nil {
-		return err
+	if a.openBrowser {
+		if err := browser.Open(problemURL); err != nil {
+			_, _ = fmt.Fprintln(a.errStream, "failed to open the problem page: "+err.Error())
+		}
 	}
 
-	if success := a.checker.Check(a.command, samples); !success {
-		return err
+	samples, err := a.client.GetSamplesContext(ctx, problemURL)
+	if err != nil {
+		return "", nil, err
 	}
+	return problemURL, samples, nil
+}
 
-	return nil
+var inlineReplacer = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t")
+
+// unescapeInline interprets escape sequences in a sample given on the command line
+// and terminates it with a newline as the samples on the problem pages are.
+func unescapeInline(s string) string {
+	s = inlineReplacer.Replace(s)
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	return s
 }
 
 const helpMessage = `atctest is a command line tool for AtCoder.
@@ -168,6 +850,8 @@ it checks if your program correctly solve the samples provided on the problem pa
 EXAMPLE: 
 $ atctest -contest ABC051 -problem C -command 'python c.py'
 $ atctest -url 'https://atcoder.jp/contests/abc051/tasks/abc051_c' -command 'g++ c.cpp; ./a.out'
+$ atctest -input '1 2' -expected '3' -command 'python add.py'
+$ ./gen.sh | atctest -stdin-samples -command 'python c.py'
 
 # for contest in session, login is required to test your code
 $ atctest -contest ABC127 -problem B -command 'ruby b.rb' -username mui87 -password pass1234
@@ -2,8 +2,14 @@ package app
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
 	"strings"
 	"testing"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mui87/atctest/atcoder"
 )
 
 func TestNew(t *testing.T) {
@@ -18,6 +24,16 @@ func TestNew(t *testing.T) {
 			inputArgs:          strings.Fields("atctest -contest ABC051 -problem C -command 'python c.py'"),
 			expectedContestURL: "https://atcoder.jp/contests/abc051",
 		},
+		{
+			name:               "success-download only without command",
+			inputArgs:          strings.Fields("atctest -download-only -contest ABC051 -problem C"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:               "success-show samples without command",
+			inputArgs:          strings.Fields("atctest -show-samples -contest ABC051 -problem C"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
 		{
 			name:               "success-with url_old",
 			inputArgs:          strings.Fields("atctest -url 'https://abc051.contest.atcoder.jp/tasks/abc051_c'"),
@@ -28,6 +44,21 @@ func TestNew(t *testing.T) {
 			inputArgs:          strings.Fields("atctest -url 'https://atcoder.jp/contests/abc051/tasks/abc051_c'"),
 			expectedContestURL: "https://atcoder.jp/contests/abc051",
 		},
+		{
+			name:               "success-with url_old with query",
+			inputArgs:          strings.Fields("atctest -url 'https://abc051.contest.atcoder.jp/tasks/abc051_c/?lang=en'"),
+			expectedContestURL: "https://abc051.contest.atcoder.jp",
+		},
+		{
+			name:               "success-with url_new with query",
+			inputArgs:          strings.Fields("atctest -url 'https://atcoder.jp/contests/abc051/tasks/abc051_c?lang=en'"),
+			expectedContestURL: "https://atcoder.jp/contests/abc051",
+		},
+		{
+			name:           "failure-url without contest",
+			inputArgs:      strings.Fields("atctest -url 'https://atcoder.jp/users/chokudai'"),
+			expectedErrMsg: "could not find the contest in the url",
+		},
 		{
 			name:           "failure-unknown option exists",
 			inputArgs:      strings.Fields("atctest -hello world -problem C -command 'python c.py'"),
@@ -48,6 +79,116 @@ func TestNew(t *testing.T) {
 			inputArgs:      strings.Fields("atctest -contest ABC051 -problem C"),
 			expectedErrMsg: "specify the command",
 		},
+		{
+			name:           "failure-unknown format",
+			inputArgs:      strings.Fields("atctest -format xml -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "unknown format",
+		},
+		{
+			name:           "failure-no jobs",
+			inputArgs:      strings.Fields("atctest -jobs 0 -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "-jobs must be 1 or more",
+		},
+		{
+			name:           "failure-unknown comparison mode",
+			inputArgs:      strings.Fields("atctest -compare xml -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "unknown comparison mode 'xml'",
+		},
+		{
+			name:           "failure-problem list url without contest",
+			inputArgs:      strings.Fields("atctest -problem-list-url /contests/abc051/tasks -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "must contain {contest}",
+		},
+		{
+			name:           "failure-invalid ignore-lines",
+			inputArgs:      strings.Fields("atctest -ignore-lines ^(DEBUG -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "-ignore-lines is not a valid regular expression",
+		},
+		{
+			name:           "failure-negative retries",
+			inputArgs:      strings.Fields("atctest -retries -1 -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "-retries must not be negative",
+		},
+		{
+			name:           "failure-negative http-timeout",
+			inputArgs:      strings.Fields("atctest -http-timeout -1s -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "-http-timeout must not be negative",
+		},
+		{
+			name:           "failure-negative cache-ttl",
+			inputArgs:      strings.Fields("atctest -cache-ttl -1h -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "-cache-ttl must not be negative",
+		},
+		{
+			name:           "failure-negative memory-limit",
+			inputArgs:      strings.Fields("atctest -memory-limit -1 -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "-memory-limit must not be negative",
+		},
+		{
+			name:           "failure-unknown input-mode",
+			inputArgs:      strings.Fields("atctest -input-mode args -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "unknown input mode",
+		},
+		{
+			name:           "failure-input-mode file without placeholder",
+			inputArgs:      strings.Fields("atctest -input-mode file -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "-command must contain {input}",
+		},
+		{
+			name:           "failure-invalid proxy",
+			inputArgs:      strings.Fields("atctest -proxy ftp://proxy.example.com -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "invalid proxy url 'ftp://proxy.example.com'",
+		},
+		{
+			name:           "failure-unknown language",
+			inputArgs:      strings.Fields("atctest -lang fr -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "unknown language 'fr'",
+		},
+		{
+			name:           "failure-unknown encoding",
+			inputArgs:      strings.Fields("atctest -encoding no-such-encoding -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "unknown encoding 'no-such-encoding'",
+		},
+		{
+			name:           "failure-conflicting trailing newline options",
+			inputArgs:      strings.Fields("atctest -require-trailing-newline -require-no-trailing-newline -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "cannot be used together",
+		},
+		{
+			name:           "failure-expected option missing",
+			inputArgs:      strings.Fields("atctest -input 1 -command cat"),
+			expectedErrMsg: "specify both -input and -expected",
+		},
+		{
+			name:           "failure-command option missing with inline sample",
+			inputArgs:      strings.Fields("atctest -input 1 -expected 1"),
+			expectedErrMsg: "specify the command",
+		},
+		{
+			name:           "failure-command option missing with stdin samples",
+			inputArgs:      strings.Fields("atctest -stdin-samples"),
+			expectedErrMsg: "specify the command",
+		},
+		{
+			name:           "failure-command option missing with html file",
+			inputArgs:      strings.Fields("atctest -html-file abc124_b.mhtml"),
+			expectedErrMsg: "specify the command",
+		},
+		{
+			name:           "failure-command option missing with samples dir",
+			inputArgs:      strings.Fields("atctest -samplesdir ./cases"),
+			expectedErrMsg: "specify the command",
+		},
+		{
+			name:           "failure-multiple problems with url",
+			inputArgs:      strings.Fields("atctest -url https://atcoder.jp/contests/abc051/tasks/abc051_c -problem A,B -command cat"),
+			expectedErrMsg: "multiple problems can be tested only with -contest",
+		},
+		{
+			name:           "failure-contest option missing with list problems",
+			inputArgs:      strings.Fields("atctest -list-problems"),
+			expectedErrMsg: "specify the contest",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -71,3 +212,495 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestNew_inlineSample(t *testing.T) {
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-input", `3\n1 2 3`, "-expected", `6`, "-command", "python sum.py"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	expected := atcoder.Sample{Input: "3\n1 2 3\n", Output: "6\n"}
+	if a.inlineSample == nil || *a.inlineSample != expected {
+		t.Fatalf("inline sample wrong. want=%+v, got=%+v", expected, a.inlineSample)
+	}
+}
+
+func TestApp_Run_inlineSample(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputArgs      []string
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name:           "success",
+			inputArgs:      []string{"atctest", "-input", `1\t2`, "-expected", `1\t2`, "-command", "cat"},
+			expectedOutput: "sample 1: SUCCESS",
+		},
+		{
+			name:           "failure",
+			inputArgs:      []string{"atctest", "-input", "1 2", "-expected", "3", "-command", "cat"},
+			expectedOutput: "sample 1: FAILURE",
+			expectedErr:    ErrSamplesFailed,
+		},
+		{
+			name:           "success-oneline",
+			inputArgs:      []string{"atctest", "-format", "oneline", "-input", "3", "-expected", "3", "-command", "cat"},
+			expectedOutput: "inline: 1/1 PASS\n",
+		},
+		{
+			name:           "failure-oneline",
+			inputArgs:      []string{"atctest", "-format", "oneline", "-input", "1 2", "-expected", "3", "-command", "cat"},
+			expectedOutput: "inline: 0/1 FAIL\n",
+			expectedErr:    ErrSamplesFailed,
+		},
+		{
+			name:           "failure-json",
+			inputArgs:      []string{"atctest", "-json", "-input", "1 2", "-expected", "3", "-command", "cat"},
+			expectedOutput: "{\n  \"problem\": \"inline\",\n  \"success\": false,\n  \"passed\": 0,\n  \"total\": 1,\n",
+			expectedErr:    ErrSamplesFailed,
+		},
+		{
+			name:           "failure-diff",
+			inputArgs:      []string{"atctest", "-diff", "-input", `1\n2\n3`, "-expected", `1\n2\n4`, "-command", "cat"},
+			expectedOutput: "diff (-expected +actual):\n@@ -1,3 +1,3 @@\n",
+			expectedErr:    ErrSamplesFailed,
+		},
+		{
+			name:           "failure-failfast",
+			inputArgs:      []string{"atctest", "-failfast", "-input", "1", "-expected", "2", "-command", "cat"},
+			expectedOutput: "sample 1: FAILURE",
+			expectedErr:    ErrSamplesFailed,
+		},
+		{
+			name:           "failure-timeout",
+			inputArgs:      []string{"atctest", "-timeout", "100ms", "-input", "1", "-expected", "1", "-command", "sleep 10 && cat"},
+			expectedOutput: "sample 1: TIMEOUT",
+			expectedErr:    ErrSamplesFailed,
+		},
+		{
+			name:           "failure-deadline",
+			inputArgs:      []string{"atctest", "-deadline", "100ms", "-input", "1", "-expected", "1", "-command", "sleep 10 && cat"},
+			expectedOutput: "sample 1: TIMEOUT",
+			expectedErr:    atcoder.ErrDeadlineExceeded,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setupHome(t)
+
+			var outStream, errStream bytes.Buffer
+			a, err := New(test.inputArgs, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if err := a.Run(); err != test.expectedErr {
+				t.Fatalf("err wrong. want=%v, got=%v", test.expectedErr, err)
+			}
+			if !strings.Contains(outStream.String(), test.expectedOutput) {
+				t.Fatalf("expect '%s' to contain '%s'", outStream.String(), test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestApp_Run_htmlFile(t *testing.T) {
+	setupHome(t)
+
+	// counts the mountains which are not lower than any mountain before them
+	command := `tail -n 1 | tr ' ' '\n' | awk '$1 >= max { count++; max = $1 } END { print count }'`
+	pagePath := path.Join("..", "atcoder", "testdata", "saved_page", "abc124b.mhtml")
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-html-file", pagePath, "-command", command}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != nil {
+		t.Fatalf("err should be nil. got: %s\n%s", err, outStream.String())
+	}
+	for _, expected := range []string{"sample 1: SUCCESS", "sample 2: SUCCESS", "sample 3: SUCCESS"} {
+		if !strings.Contains(outStream.String(), expected) {
+			t.Fatalf("expect '%s' to contain '%s'", outStream.String(), expected)
+		}
+	}
+}
+
+func TestApp_Run_samplesDir(t *testing.T) {
+	home := setupHome(t)
+
+	dir := path.Join(home, "cases")
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	for name, content := range map[string]string{"1.in": "1 2\n", "1.out": "3\n", "2.in": "3 4\n", "2.out": "8\n"} {
+		if err := ioutil.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+	}
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-samplesdir", dir, "-command", "awk '{ print $1 + $2 }'"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != ErrSamplesFailed {
+		t.Fatalf("err wrong. want=%v, got=%v", ErrSamplesFailed, err)
+	}
+	for _, expected := range []string{"sample 1: SUCCESS", "sample 2: FAILURE"} {
+		if !strings.Contains(outStream.String(), expected) {
+			t.Fatalf("expect '%s' to contain '%s'", outStream.String(), expected)
+		}
+	}
+}
+
+func TestApp_Run_downloadOnly(t *testing.T) {
+	home := setupHome(t)
+
+	pagePath := path.Join("..", "atcoder", "testdata", "saved_page", "abc124b.mhtml")
+	dumpDir := path.Join(home, "cases")
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-html-file", pagePath, "-dump", dumpDir, "-download-only"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if outStream.String() != "3 samples are downloaded\n" {
+		t.Fatalf("nothing should be run. got: %s", outStream.String())
+	}
+	expected := "3 samples are written to " + dumpDir
+	if !strings.Contains(errStream.String(), expected) {
+		t.Fatalf("expect '%s' to contain '%s'", errStream.String(), expected)
+	}
+
+	output, err := ioutil.ReadFile(path.Join(dumpDir, "2.out"))
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if string(output) != "3\n" {
+		t.Fatalf("output of sample 2 wrong. want=%q, got=%q", "3\n", string(output))
+	}
+}
+
+func TestApp_Run_noSamples(t *testing.T) {
+	setupHome(t)
+
+	pagePath := path.Join("..", "atcoder", "testdata", "problem", "interactive.html")
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-html-file", pagePath, "-command", "cat"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	expected := "this problem has no downloadable samples"
+	if !strings.Contains(outStream.String(), expected) {
+		t.Fatalf("expect '%s' to contain '%s'", outStream.String(), expected)
+	}
+}
+
+func TestApp_logIn_credentials(t *testing.T) {
+	tests := []struct {
+		name string
+
+		inputArgs   []string
+		inputEnv    map[string]string
+		inputConfig string
+
+		expectedUsername string
+		expectedPassword string
+	}{
+		{
+			name:             "from flags",
+			inputArgs:        strings.Fields("atctest -username flaguser -password flagpass -contest ABC051 -problem C -command 'python c.py'"),
+			inputEnv:         map[string]string{envUsername: "envuser", envPassword: "envpass"},
+			inputConfig:      `{"username": "configuser", "password": "configpass"}`,
+			expectedUsername: "flaguser",
+			expectedPassword: "flagpass",
+		},
+		{
+			name:             "from env",
+			inputArgs:        strings.Fields("atctest -contest ABC051 -problem C -command 'python c.py'"),
+			inputEnv:         map[string]string{envUsername: "envuser", envPassword: "envpass"},
+			inputConfig:      `{"username": "configuser", "password": "configpass"}`,
+			expectedUsername: "envuser",
+			expectedPassword: "envpass",
+		},
+		{
+			name:             "from config",
+			inputArgs:        strings.Fields("atctest -contest ABC051 -problem C -command 'python c.py'"),
+			inputConfig:      `{"username": "configuser", "password": "configpass"}`,
+			expectedUsername: "configuser",
+			expectedPassword: "configpass",
+		},
+		{
+			name:             "mixed sources",
+			inputArgs:        strings.Fields("atctest -username flaguser -contest ABC051 -problem C -command 'python c.py'"),
+			inputEnv:         map[string]string{envPassword: "envpass"},
+			inputConfig:      `{"username": "configuser", "password": "configpass"}`,
+			expectedUsername: "flaguser",
+			expectedPassword: "envpass",
+		},
+		{
+			name:      "no credentials",
+			inputArgs: strings.Fields("atctest -contest ABC051 -problem C -command 'python c.py'"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			home := setupHome(t)
+			for _, key := range []string{envUsername, envPassword} {
+				t.Setenv(key, test.inputEnv[key])
+			}
+			if test.inputConfig != "" {
+				writeConfig(t, home, test.inputConfig)
+			}
+
+			var username, password string
+			defer func(original func(*atcoder.Client, string, string) error) { logIn = original }(logIn)
+			logIn = func(_ *atcoder.Client, u, p string) error {
+				username, password = u, p
+				return nil
+			}
+			defer func(original func(int) bool) { isTerminal = original }(isTerminal)
+			isTerminal = func(int) bool { return false }
+
+			var outStream, errStream bytes.Buffer
+			a, err := New(test.inputArgs, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if err := a.logIn(); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if username != test.expectedUsername {
+				t.Fatalf("username passed to LogIn wrong. want=%q, got=%q", test.expectedUsername, username)
+			}
+			if password != test.expectedPassword {
+				t.Fatalf("password passed to LogIn wrong. want=%q, got=%q", test.expectedPassword, password)
+			}
+		})
+	}
+}
+
+func TestNew_config(t *testing.T) {
+	home := setupHome(t)
+	t.Setenv(envUsername, "")
+	t.Setenv(envPassword, "")
+	writeConfig(t, home, `{"username": "defaultuser", "password": "defaultpass"}`)
+
+	explicitPath := path.Join(home, "project.json")
+	if err := ioutil.WriteFile(explicitPath, []byte(`{"username": "projectuser", "password": "projectpass"}`), 0600); err != nil {
+		t.Fatalf("failed to write config file: %s", err)
+	}
+
+	t.Run("explicit config", func(t *testing.T) {
+		var outStream, errStream bytes.Buffer
+		a, err := New([]string{"atctest", "-config", explicitPath, "-contest", "ABC051", "-problem", "C", "-command", "cat"}, &outStream, &errStream)
+		if err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+		if a.username != "projectuser" || a.password != "projectpass" {
+			t.Fatalf("credentials wrong. want=(projectuser, projectpass), got=(%s, %s)", a.username, a.password)
+		}
+	})
+
+	t.Run("missing explicit config", func(t *testing.T) {
+		var outStream, errStream bytes.Buffer
+		_, err := New([]string{"atctest", "-config", path.Join(home, "missing.json"), "-contest", "ABC051", "-problem", "C", "-command", "cat"}, &outStream, &errStream)
+		if err == nil {
+			t.Fatal("err should not be nil. got: nil")
+		}
+		if !strings.Contains(err.Error(), "failed to read config file") {
+			t.Fatalf("expect '%s' to contain '%s'", err.Error(), "failed to read config file")
+		}
+	})
+
+	t.Run("readable config", func(t *testing.T) {
+		readablePath := path.Join(home, "readable.json")
+		if err := ioutil.WriteFile(readablePath, []byte(`{"username": "projectuser", "password": "projectpass"}`), 0644); err != nil {
+			t.Fatalf("failed to write config file: %s", err)
+		}
+		// the file mode is masked by umask on creation
+		if err := os.Chmod(readablePath, 0644); err != nil {
+			t.Fatalf("failed to change the mode of config file: %s", err)
+		}
+
+		for _, configPath := range []string{explicitPath, readablePath} {
+			var outStream, errStream bytes.Buffer
+			if _, err := New([]string{"atctest", "-config", configPath, "-contest", "ABC051", "-problem", "C", "-command", "cat"}, &outStream, &errStream); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			warned := strings.Contains(errStream.String(), "readable by everyone")
+			if warned != (configPath == readablePath) {
+				t.Fatalf("warning for %s wrong. got: '%s'", configPath, errStream.String())
+			}
+		}
+	})
+}
+
+func TestNew_session(t *testing.T) {
+	tests := []struct {
+		name                  string
+		inputArgs             []string
+		inputJarPerm          os.FileMode
+		expectedCookieJarPath string
+		expectedErrMsg        string
+	}{
+		{
+			name:                  "success-session in the cache directory",
+			inputArgs:             strings.Fields("atctest -contest ABC051 -problem C -command cat"),
+			inputJarPerm:          0600,
+			expectedCookieJarPath: path.Join(".atctest", "session.jar"),
+		},
+		{
+			name:                  "success-session is not loaded on relogin",
+			inputArgs:             strings.Fields("atctest -relogin -contest ABC051 -problem C -command cat"),
+			inputJarPerm:          0644,
+			expectedCookieJarPath: path.Join(".atctest", "session.jar"),
+		},
+		{
+			name:         "success-session is not saved with read-only cache",
+			inputArgs:    strings.Fields("atctest -read-only-cache -contest ABC051 -problem C -command cat"),
+			inputJarPerm: 0600,
+		},
+		{
+			name:           "failure-session accessible by other users",
+			inputArgs:      strings.Fields("atctest -contest ABC051 -problem C -command cat"),
+			inputJarPerm:   0644,
+			expectedErrMsg: "accessible by other users",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			home := setupHome(t)
+			jarPath := path.Join(home, ".atctest", "session.jar")
+			if err := os.MkdirAll(path.Dir(jarPath), 0700); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if err := ioutil.WriteFile(jarPath, []byte("[]"), test.inputJarPerm); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			a, err := New(test.inputArgs, ioutil.Discard, ioutil.Discard)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			expected := test.expectedCookieJarPath
+			if expected != "" {
+				expected = path.Join(home, expected)
+			}
+			if a.cookieJarPath != expected {
+				t.Fatalf("cookieJarPath wrong. want=%s, got=%s", expected, a.cookieJarPath)
+			}
+		})
+	}
+}
+
+func TestNew_cacheDir(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputArgs            []string
+		inputEnvCacheDir     string
+		expectedCacheDirPath string
+		expectedErrMsg       string
+	}{
+		{
+			name:                 "success-default",
+			inputArgs:            strings.Fields("atctest -contest ABC051 -problem C -command cat"),
+			expectedCacheDirPath: ".atctest",
+		},
+		{
+			name:                 "success-flag",
+			inputArgs:            strings.Fields("atctest -cachedir ~/project/.cache -contest ABC051 -problem C -command cat"),
+			expectedCacheDirPath: "project/.cache",
+		},
+		{
+			name:                 "success-env",
+			inputArgs:            strings.Fields("atctest -contest ABC051 -problem C -command cat"),
+			inputEnvCacheDir:     "~/env-cache",
+			expectedCacheDirPath: "env-cache",
+		},
+		{
+			name:                 "success-flag over env",
+			inputArgs:            strings.Fields("atctest -cachedir ~/flag-cache -contest ABC051 -problem C -command cat"),
+			inputEnvCacheDir:     "~/env-cache",
+			expectedCacheDirPath: "flag-cache",
+		},
+		{
+			name:           "failure-not writable",
+			inputArgs:      strings.Fields("atctest -cachedir ~/file/cache -contest ABC051 -problem C -command cat"),
+			expectedErrMsg: "cache directory cannot be used",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			home := setupHome(t)
+			t.Setenv("ATCTEST_CACHE_DIR", test.inputEnvCacheDir)
+			if err := ioutil.WriteFile(path.Join(home, "file"), nil, 0644); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			a, err := New(test.inputArgs, ioutil.Discard, ioutil.Discard)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			expected := path.Join(home, test.expectedCacheDirPath)
+			if a.cacheDirPath != expected {
+				t.Fatalf("cacheDirPath wrong. want=%s, got=%s", expected, a.cacheDirPath)
+			}
+		})
+	}
+}
+
+func setupHome(t *testing.T) string {
+	home, err := ioutil.TempDir("", "atctest-home")
+	if err != nil {
+		t.Fatalf("failed to create dummy home dir: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(home)
+	})
+
+	homedir.DisableCache = true
+	t.Setenv("HOME", home)
+
+	return home
+}
+
+func writeConfig(t *testing.T, home, content string) {
+	dir := path.Join(home, ".atctest")
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		t.Fatalf("failed to create config dir: %s", err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, configFileName), []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config file: %s", err)
+	}
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mui87/atctest/commander"
+)
+
+// build runs the command of -build once before the samples, e.g.) to compile your program.
+// the output of a successful build, e.g.) warnings of the compiler, is written to errStream.
+func (a *App) build() error {
+	if a.buildCommand == "" {
+		return nil
+	}
+
+	output, err := commander.NewCommand(a.buildCommand).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to build with '%s': %s\n%s", a.buildCommand, err, strings.TrimRight(string(output), "\n"))
+	}
+	_, _ = a.errStream.Write(output)
+	return nil
+}
+
+// resultKey returns the command the result cache is keyed by, which includes the build command,
+// so that the changes to the source files referenced only by -build are noticed as well.
+func (a *App) resultKey() string {
+	if a.buildCommand == "" {
+		return a.command
+	}
+	return a.buildCommand + " && " + a.command
+}
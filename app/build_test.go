@@ -0,0 +1,82 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestApp_Run_build(t *testing.T) {
+	tests := []struct {
+		name       string
+		inputBuild string
+
+		expectedErrMsg string
+		expectedBuilds int
+		expectedOutput string
+	}{
+		{
+			name:           "success",
+			inputBuild:     "echo built >> {dir}/build.log && printf 'awk \"{ print \\$1 + \\$2 }\"' > {dir}/prog && chmod +x {dir}/prog",
+			expectedBuilds: 1,
+			expectedOutput: "sample 2: SUCCESS",
+		},
+		{
+			name:           "failure-compile error",
+			inputBuild:     "echo built >> {dir}/build.log && echo 'c.cpp:1:1: error' && exit 1",
+			expectedErrMsg: "failed to build with",
+			expectedBuilds: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			home := setupHome(t)
+			dir := path.Join(home, "cases")
+			if err := os.MkdirAll(dir, 0777); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			for name, content := range map[string]string{"1.in": "1 2\n", "1.out": "3\n", "2.in": "3 4\n", "2.out": "7\n"} {
+				if err := ioutil.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+			}
+
+			build := strings.Replace(test.inputBuild, "{dir}", dir, -1)
+			var outStream, errStream bytes.Buffer
+			a, err := New([]string{"atctest", "-samplesdir", dir, "-build", build, "-command", "sh " + path.Join(dir, "prog")}, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			err = a.Run()
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) || !strings.Contains(err.Error(), "c.cpp:1:1: error") {
+					t.Fatalf("expect '%s' to contain '%s' and the output of the build", err.Error(), test.expectedErrMsg)
+				}
+				if outStream.String() != "" {
+					t.Fatalf("samples should not be run. got: %s", outStream.String())
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				if !strings.Contains(outStream.String(), test.expectedOutput) {
+					t.Fatalf("expect '%s' to contain '%s'", outStream.String(), test.expectedOutput)
+				}
+			}
+
+			log, err := ioutil.ReadFile(path.Join(dir, "build.log"))
+			if err != nil {
+				t.Fatalf("failed to read the build log: %s", err)
+			}
+			if builds := strings.Count(string(log), "built"); builds != test.expectedBuilds {
+				t.Fatalf("number of builds wrong. want=%d, got=%d", test.expectedBuilds, builds)
+			}
+		})
+	}
+}
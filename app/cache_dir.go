@@ -0,0 +1,49 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// envCacheDir is the environment variable for the cache directory, overridden by -cachedir.
+const envCacheDir = "ATCTEST_CACHE_DIR"
+
+// resolveCacheDir returns the cache directory given by -cachedir or ATCTEST_CACHE_DIR, or ~/.atctest by default,
+// and whether it is given explicitly. it is empty if none is given and the home directory is not resolvable.
+func resolveCacheDir(flagValue string) (string, bool, error) {
+	dirPath := flagValue
+	if dirPath == "" {
+		dirPath = os.Getenv(envCacheDir)
+	}
+	if dirPath != "" {
+		expanded, err := homedir.Expand(dirPath)
+		if err != nil {
+			return "", false, err
+		}
+		return expanded, true, nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", false, nil
+	}
+	return path.Join(home, ".atctest"), false, nil
+}
+
+// checkWritable creates the directory if needed and checks that a file can be created in it.
+func checkWritable(dirPath string) error {
+	if err := os.MkdirAll(dirPath, 0777); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dirPath, "check")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %s", dirPath, err)
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	return nil
+}
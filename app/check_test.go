@@ -0,0 +1,44 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestApp_Run_check(t *testing.T) {
+	setupHome(t)
+
+	tests := []struct {
+		name          string
+		inputExpected string
+		expectedErr   error
+	}{
+		{
+			name:          "pass",
+			inputExpected: "1",
+			expectedErr:   nil,
+		},
+		{
+			name:          "fail",
+			inputExpected: "2",
+			expectedErr:   ErrSamplesFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream, errStream bytes.Buffer
+			a, err := New([]string{"atctest", "-check", "-input", "1", "-expected", test.inputExpected, "-command", "cat"}, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			if err := a.Run(); err != test.expectedErr {
+				t.Fatalf("err wrong. want=%v, got=%v", test.expectedErr, err)
+			}
+			if outStream.Len() != 0 || errStream.Len() != 0 {
+				t.Fatalf("nothing should be printed. got:\n%s%s", outStream.String(), errStream.String())
+			}
+		})
+	}
+}
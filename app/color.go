@@ -0,0 +1,24 @@
+package app
+
+import (
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// envNoColor disables the colors of the output when it is set to any value but empty. see https://no-color.org
+const envNoColor = "NO_COLOR"
+
+// useColor reports whether the output is colored.
+// the colors are disabled by -no-color or $NO_COLOR, and when the output is not a terminal, e.g.) a pipe or a file.
+func useColor(noColor bool, outStream io.Writer) bool {
+	if noColor || os.Getenv(envNoColor) != "" {
+		return false
+	}
+	f, ok := outStream.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestUseColor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atctest")
+	if err != nil {
+		t.Fatalf("failed to create a temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	file, err := os.Create(path.Join(dir, "out.log"))
+	if err != nil {
+		t.Fatalf("failed to create a file: %s", err)
+	}
+	defer file.Close()
+
+	tests := []struct {
+		name          string
+		inputNoColor  bool
+		inputEnv      string
+		inputStream   io.Writer
+		expectedColor bool
+	}{
+		{
+			name:          "-no-color",
+			inputNoColor:  true,
+			inputStream:   os.Stdout,
+			expectedColor: false,
+		},
+		{
+			name:          "NO_COLOR",
+			inputEnv:      "1",
+			inputStream:   os.Stdout,
+			expectedColor: false,
+		},
+		{
+			name:          "buffer",
+			inputStream:   &bytes.Buffer{},
+			expectedColor: false,
+		},
+		{
+			name:          "file",
+			inputStream:   file,
+			expectedColor: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(envNoColor, test.inputEnv)
+			if got := useColor(test.inputNoColor, test.inputStream); got != test.expectedColor {
+				t.Fatalf("useColor wrong. want=%t, got=%t", test.expectedColor, got)
+			}
+		})
+	}
+}
+
+func TestNew_noColor(t *testing.T) {
+	setupHome(t)
+	original := color.NoColor
+	defer func() { color.NoColor = original }()
+
+	color.NoColor = false
+	var outStream, errStream bytes.Buffer
+	if _, err := New([]string{"atctest", "-no-color", "-input", "1", "-expected", "1", "-command", "cat"}, &outStream, &errStream); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if !color.NoColor {
+		t.Fatalf("colors should be disabled")
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
+)
+
+const (
+	envUsername = "ATCODER_USERNAME"
+	envPassword = "ATCODER_PASSWORD"
+
+	configFileName = "config.json"
+	// sessionFileName is the cookie jar in the cache directory, used unless -cookie-jar is given
+	sessionFileName = "session.jar"
+)
+
+type config struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// ProblemListURL is the pattern of the URL of the problem list page, e.g.) "/contests/{contest}/tasks"
+	ProblemListURL string `json:"problem_list_url"`
+}
+
+// loadConfig reads the config file. a missing file is an error only when it is explicitly specified.
+func loadConfig(configFilePath string, explicit bool) (*config, error) {
+	if configFilePath == "" {
+		return &config{}, nil
+	}
+
+	bytes, err := ioutil.ReadFile(configFilePath)
+	if os.IsNotExist(err) && !explicit {
+		return &config{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %s", err)
+	}
+
+	var cfg config
+	if err := json.Unmarshal(bytes, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %s", configFilePath, err)
+	}
+
+	return &cfg, nil
+}
+
+// warnReadableConfig warns if the config file with a password in it can be read by other users.
+// the permission is not checked on Windows, where it does not tell who can read the file.
+func warnReadableConfig(configFilePath string, cfg *config, errStream io.Writer) {
+	if cfg.Password == "" || runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(configFilePath)
+	if err != nil || info.Mode().Perm()&0004 == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(errStream, "[WARNING] the config file %s has your password but is readable by everyone. run 'chmod 600 %s'\n", configFilePath, configFilePath)
+}
+
+// resolveCredential returns the first non-empty value in the order of flag, environment variable and config file.
+func resolveCredential(flagValue, envKey, configValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if v := os.Getenv(envKey); v != "" {
+		return v
+	}
+	return configValue
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// legacyContestHostSuffix is the suffix of the hosts of the older contests, e.g.) abc051.contest.atcoder.jp.
+const legacyContestHostSuffix = ".contest.atcoder.jp"
+
+// contestURLOf returns the top page of the contest which the problem url belongs to, in the host style of the url.
+// e.g.) 'https://atcoder.jp/contests/abc051/tasks/abc051_c?lang=en' -> 'https://atcoder.jp/contests/abc051'
+// e.g.) 'https://abc051.contest.atcoder.jp/tasks/abc051_c' -> 'https://abc051.contest.atcoder.jp'
+func contestURLOf(problemURL string) (string, error) {
+	u, err := url.Parse(problemURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid url of the problem page: %s", problemURL)
+	}
+
+	if strings.HasSuffix(u.Hostname(), legacyContestHostSuffix) {
+		return fmt.Sprintf("%s://%s", u.Scheme, u.Host), nil
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "contests" || segments[1] == "" {
+		return "", fmt.Errorf("could not find the contest in the url: %s", problemURL)
+	}
+	return fmt.Sprintf("%s://%s/contests/%s", u.Scheme, u.Host, segments[1]), nil
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mitchellh/go-homedir"
+)
+
+// errCheckSkipped is returned by a check of the doctor when it has nothing to check.
+var errCheckSkipped = errors.New("skipped")
+
+type doctorCheck struct {
+	name  string
+	check func() (string, error)
+}
+
+// runDoctor checks the environment step by step and prints the result of each check.
+func (a *App) runDoctor() error {
+	checks := []doctorCheck{
+		{name: "home directory", check: a.checkHomeDir},
+		{name: "cache directory", check: a.checkCacheDir},
+		{name: "network", check: a.checkNetwork},
+		{name: "credentials", check: a.checkCredentials},
+		{name: "command", check: a.checkCommand},
+	}
+
+	failed := 0
+	for _, c := range checks {
+		detail, err := c.check()
+		switch {
+		case err == errCheckSkipped:
+			_, _ = color.New(color.FgYellow).Fprint(a.outStream, "[SKIP]")
+		case err != nil:
+			failed++
+			detail = err.Error()
+			_, _ = color.New(color.FgRed).Fprint(a.outStream, "[FAIL]")
+		default:
+			_, _ = color.New(color.FgGreen).Fprint(a.outStream, "[PASS]")
+		}
+		_, _ = fmt.Fprintf(a.outStream, " %s: %s\n", c.name, detail)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+func (a *App) checkHomeDir() (string, error) {
+	return homedir.Dir()
+}
+
+func (a *App) checkCacheDir() (string, error) {
+	if a.cacheDirPath == "" {
+		return "", errors.New("could not be determined because the home directory is not resolvable")
+	}
+	if err := checkWritable(a.cacheDirPath); err != nil {
+		return "", err
+	}
+	return a.cacheDirPath, nil
+}
+
+func (a *App) checkNetwork() (string, error) {
+	if err := a.client.CheckReachable(); err != nil {
+		return "", err
+	}
+	return baseURL + " is reachable", nil
+}
+
+func (a *App) checkCredentials() (string, error) {
+	if a.username == "" || a.password == "" {
+		return "username/password not provided", errCheckSkipped
+	}
+	if err := a.client.LogIn(a.username, a.password); err != nil {
+		return "", err
+	}
+	return "logged in as " + a.username, nil
+}
+
+func (a *App) checkCommand() (string, error) {
+	words := strings.Fields(a.command)
+	if len(words) == 0 {
+		return "command not provided", errCheckSkipped
+	}
+	executable, err := exec.LookPath(words[0])
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not found in PATH", words[0])
+	}
+	return executable, nil
+}
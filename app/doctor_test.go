@@ -0,0 +1,82 @@
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestApp_Run_doctor(t *testing.T) {
+	tests := []struct {
+		name string
+
+		inputArgs []string
+
+		expectedOutputs []string
+		expectedErrMsg  string
+	}{
+		{
+			name:      "success",
+			inputArgs: []string{"atctest", "-doctor", "-command", "cat"},
+			expectedOutputs: []string{
+				"[PASS] home directory",
+				"[PASS] cache directory",
+				"[PASS] network: https://atcoder.jp is reachable",
+				"[SKIP] credentials: username/password not provided",
+				"[PASS] command",
+			},
+		},
+		{
+			name:      "failure-command_not_found",
+			inputArgs: []string{"atctest", "-doctor", "-command", "atctest-nonexistent-command a.py"},
+			expectedOutputs: []string{
+				"[PASS] network",
+				"[FAIL] command: 'atctest-nonexistent-command' is not found in PATH",
+			},
+			expectedErrMsg: "1 of 5 checks failed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setupHome(t)
+			t.Setenv(envUsername, "")
+			t.Setenv(envPassword, "")
+
+			defer gock.Off()
+			gock.New(baseURL).
+				Get("/").
+				Reply(http.StatusOK).
+				AddHeader("Content-Type", "text/html").
+				BodyString("<html></html>")
+
+			var outStream, errStream bytes.Buffer
+			a, err := New(test.inputArgs, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			err = a.Run()
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+			} else {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			}
+			for _, expected := range test.expectedOutputs {
+				if !strings.Contains(outStream.String(), expected) {
+					t.Errorf("expect '%s' to contain '%s'", outStream.String(), expected)
+				}
+			}
+		})
+	}
+}
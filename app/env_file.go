@@ -0,0 +1,56 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// loadEnvFile sets the KEY=VALUE pairs in the file to the environment variables, e.g.) ATCODER_USERNAME=chokudai.
+// the variables already set to non-empty values in the environment take precedence over the file.
+func loadEnvFile(envFilePath string) error {
+	f, err := os.Open(envFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read env file: %s", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		i := strings.Index(line, "=")
+		if i <= 0 {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE. got: %s", envFilePath, lineNumber, line)
+		}
+		key := strings.TrimSpace(line[:i])
+		value := unquoteEnvValue(strings.TrimSpace(line[i+1:]))
+
+		if os.Getenv(key) != "" {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("%s:%d: %s", envFilePath, lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read env file: %s", err)
+	}
+
+	return nil
+}
+
+func unquoteEnvValue(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
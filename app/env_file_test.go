@@ -0,0 +1,81 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestNew_envFile(t *testing.T) {
+	tests := []struct {
+		name string
+
+		inputEnvFile string
+		inputEnv     map[string]string
+
+		expectedUsername string
+		expectedPassword string
+		expectedErrMsg   string
+	}{
+		{
+			name: "success",
+			inputEnvFile: strings.Join([]string{
+				"# credentials",
+				"",
+				envUsername + "=fileuser",
+				"export " + envPassword + "='file pass'",
+			}, "\n"),
+			expectedUsername: "fileuser",
+			expectedPassword: "file pass",
+		},
+		{
+			name:             "success-environment takes precedence",
+			inputEnvFile:     envUsername + "=fileuser\n" + envPassword + "=filepass\n",
+			inputEnv:         map[string]string{envPassword: "envpass"},
+			expectedUsername: "fileuser",
+			expectedPassword: "envpass",
+		},
+		{
+			name:           "failure-malformed line",
+			inputEnvFile:   envUsername + "=fileuser\nhello\n",
+			expectedErrMsg: ".env:2: expected KEY=VALUE",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			home := setupHome(t)
+			for _, key := range []string{envUsername, envPassword} {
+				t.Setenv(key, test.inputEnv[key])
+			}
+			envFilePath := path.Join(home, ".env")
+			if err := ioutil.WriteFile(envFilePath, []byte(test.inputEnvFile), 0600); err != nil {
+				t.Fatalf("failed to write env file: %s", err)
+			}
+
+			var outStream, errStream bytes.Buffer
+			a, err := New([]string{"atctest", "-env-file", envFilePath, "-contest", "ABC051", "-problem", "C", "-command", "cat"}, &outStream, &errStream)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if a.username != test.expectedUsername {
+				t.Fatalf("username wrong. want=%q, got=%q", test.expectedUsername, a.username)
+			}
+			if a.password != test.expectedPassword {
+				t.Fatalf("password wrong. want=%q, got=%q", test.expectedPassword, a.password)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+type exampleLanguage struct {
+	name    string
+	command string // %s is replaced with the source file name without its extension
+}
+
+var exampleLanguages = []exampleLanguage{
+	{name: "Python", command: "python %s.py"},
+	{name: "C++", command: "g++ %s.cpp && ./a.out"},
+	{name: "Java", command: "javac Main.java && java Main"},
+	{name: "Rust", command: "rustc %s.rs && ./%[1]s"},
+	{name: "Go", command: "go run %s.go"},
+}
+
+// printExamples prints the commands for common languages, filled with the contest and problem given by the user.
+func (a *App) printExamples() {
+	target := "-contest ABC051 -problem C"
+	problem := "c"
+	switch {
+	case a.problemURL != "":
+		target = fmt.Sprintf("-url '%s'", a.problemURL)
+		if a.problem != "" {
+			problem = strings.ToLower(a.problem)
+		}
+	case a.contest != "" || a.problem != "":
+		contest, p := "ABC051", "C"
+		if a.contest != "" {
+			contest = strings.ToUpper(a.contest)
+		}
+		if a.problem != "" {
+			p = strings.ToUpper(a.problem)
+		}
+		target = fmt.Sprintf("-contest %s -problem %s", contest, p)
+		problem = strings.ToLower(p)
+	}
+
+	for _, lang := range exampleLanguages {
+		command := lang.command
+		if strings.Contains(command, "%") {
+			command = fmt.Sprintf(command, problem)
+		}
+		_, _ = fmt.Fprintf(a.outStream, "# %s\natctest %s -command '%s'\n", lang.name, target, command)
+	}
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestApp_Run_examples(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputArgs []string
+		expected  []string
+	}{
+		{
+			name:      "default",
+			inputArgs: []string{"atctest", "-examples"},
+			expected: []string{
+				"atctest -contest ABC051 -problem C -command 'python c.py'",
+				"atctest -contest ABC051 -problem C -command 'g++ c.cpp && ./a.out'",
+				"atctest -contest ABC051 -problem C -command 'javac Main.java && java Main'",
+				"atctest -contest ABC051 -problem C -command 'rustc c.rs && ./c'",
+				"atctest -contest ABC051 -problem C -command 'go run c.go'",
+			},
+		},
+		{
+			name:      "contest_and_problem",
+			inputArgs: []string{"atctest", "-examples", "-contest", "abc087", "-problem", "b"},
+			expected: []string{
+				"atctest -contest ABC087 -problem B -command 'python b.py'",
+				"atctest -contest ABC087 -problem B -command 'go run b.go'",
+			},
+		},
+		{
+			name:      "url",
+			inputArgs: []string{"atctest", "-examples", "-url", "https://atcoder.jp/contests/abc051/tasks/abc051_c"},
+			expected: []string{
+				"atctest -url 'https://atcoder.jp/contests/abc051/tasks/abc051_c' -command 'python c.py'",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream, errStream bytes.Buffer
+			a, err := New(test.inputArgs, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if err := a.Run(); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(outStream.String(), expected) {
+					t.Errorf("expect '%s' to contain '%s'", outStream.String(), expected)
+				}
+			}
+		})
+	}
+}
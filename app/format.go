@@ -0,0 +1,158 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mui87/atctest/atcoder"
+)
+
+const (
+	formatText    = "text"
+	formatOneline = "oneline"
+	formatJSON    = "json"
+)
+
+// jsonResult is the document printed with -format json.
+type jsonResult struct {
+	Problem string `json:"problem"`
+	Success bool   `json:"success"`
+	Passed  int    `json:"passed"`
+	Total   int    `json:"total"`
+	// Unchanged is whether the result is of the last run reported by -skip-unchanged, whose samples are not included.
+	Unchanged bool         `json:"unchanged,omitempty"`
+	Samples   []jsonSample `json:"samples"`
+}
+
+type jsonSample struct {
+	Number         int     `json:"number"`
+	Status         string  `json:"status"`
+	AllowedFailure bool    `json:"allowed_failure,omitempty"`
+	Input          string  `json:"input"`
+	Expected       string  `json:"expected"`
+	Actual         string  `json:"actual"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	ExitCode       int     `json:"exit_code"`
+	MaxRSSBytes    int64   `json:"max_rss_bytes,omitempty"`
+	Error          string  `json:"error,omitempty"`
+	Stderr         string  `json:"stderr,omitempty"`
+	CheckerMessage string  `json:"checker_message,omitempty"`
+}
+
+// printOneline prints the results in a line like 'ABC051/C: 4/6 PASS'.
+func (a *App) printOneline(problemKey string, results []atcoder.Result) {
+	failed, allowed := a.countFailures(results)
+	passed := len(results) - failed - allowed
+
+	_, _ = fmt.Fprintf(a.outStream, "%s: %d/%d %s%s\n", a.problemLabel(problemKey), passed, len(results), verdict(failed == 0), allowedNote(allowed))
+}
+
+// printJSON prints the results as a JSON document for machines, e.g.) CI.
+func (a *App) printJSON(problemKey string, results []atcoder.Result) error {
+	failed, allowed := a.countFailures(results)
+	doc := jsonResult{
+		Problem: a.problemLabel(problemKey),
+		Success: failed == 0,
+		Passed:  len(results) - failed - allowed,
+		Total:   len(results),
+		Samples: []jsonSample{},
+	}
+	for _, result := range results {
+		sample := jsonSample{
+			Number:         result.Number(),
+			Status:         result.Status.String(),
+			AllowedFailure: !result.Success() && a.allowFail[result.Number()],
+			Input:          result.Sample.Input,
+			Expected:       result.Sample.Output,
+			Actual:         result.Actual,
+			ElapsedSeconds: result.Elapsed.Seconds(),
+			ExitCode:       result.ExitCode,
+			MaxRSSBytes:    result.MaxRSS,
+			Stderr:         result.Stderr,
+			CheckerMessage: result.CheckerMessage,
+		}
+		if result.Err != nil {
+			sample.Error = result.Err.Error()
+		}
+		doc.Samples = append(doc.Samples, sample)
+	}
+
+	return a.encodeJSON(doc)
+}
+
+func (a *App) encodeJSON(doc jsonResult) error {
+	encoder := json.NewEncoder(a.outStream)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// resultSummary returns a plaintext summary of the results to share, with the difference of the first failed sample.
+func (a *App) resultSummary(problemKey string, results []atcoder.Result) string {
+	var buf bytes.Buffer
+	failed, allowed := a.countFailures(results)
+	passed := len(results) - failed - allowed
+	_, _ = fmt.Fprintf(&buf, "%s: %d/%d %s%s\n", a.problemLabel(problemKey), passed, len(results), verdict(failed == 0), allowedNote(allowed))
+
+	var firstFailure *atcoder.Result
+	for i, result := range results {
+		_, _ = fmt.Fprintf(&buf, "sample %d: %s\n", result.Number(), result.Status)
+		if result.Status == atcoder.StatusFailure && firstFailure == nil {
+			firstFailure = &results[i]
+		}
+	}
+
+	if firstFailure != nil {
+		_, _ = fmt.Fprintf(&buf, "diff of sample %d (-expected +actual):\n", firstFailure.Number())
+		_, _ = fmt.Fprint(&buf, atcoder.Diff(firstFailure.Sample.Output, firstFailure.Actual, atcoder.DiffOptions{Context: 3}))
+	}
+	return buf.String()
+}
+
+// printCachedResult prints the verdict of the last run reported by -skip-unchanged.
+func (a *App) printCachedResult(problemKey string, samples []atcoder.Sample, success bool) error {
+	switch a.format {
+	case formatOneline:
+		_, _ = fmt.Fprintf(a.outStream, "%s: %s (unchanged)\n", a.problemLabel(problemKey), verdict(success))
+		return nil
+	case formatJSON:
+		failed, _ := a.resultCache.failedNumbers(problemKey, a.resultKey(), samples)
+		return a.encodeJSON(jsonResult{
+			Problem:   a.problemLabel(problemKey),
+			Success:   success,
+			Passed:    len(samples) - len(failed),
+			Total:     len(samples),
+			Unchanged: true,
+			Samples:   []jsonSample{},
+		})
+	}
+
+	_, _ = fmt.Fprint(a.outStream, "unchanged since the last run: ")
+	if success {
+		_, _ = color.New(color.FgGreen).Fprintln(a.outStream, "SUCCESS")
+	} else {
+		_, _ = color.New(color.FgRed).Fprintln(a.outStream, "FAILURE")
+	}
+	return nil
+}
+
+// problemLabel returns a short name of the problem like 'ABC051/C'.
+func (a *App) problemLabel(problemKey string) string {
+	if a.contest != "" && a.problem != "" {
+		return strings.ToUpper(a.contest) + "/" + strings.ToUpper(a.problem)
+	}
+	if a.problemURL != "" {
+		return path.Base(strings.TrimRight(a.problemURL, "/"))
+	}
+	return problemKey
+}
+
+func verdict(success bool) string {
+	if success {
+		return "PASS"
+	}
+	return "FAIL"
+}
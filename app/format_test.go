@@ -0,0 +1,107 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+func TestApp_resultSummary(t *testing.T) {
+	a := &App{contest: "abc051", problem: "c"}
+	results := []atcoder.Result{
+		{Index: 0, Sample: atcoder.Sample{Input: "1\n", Output: "1\n"}, Status: atcoder.StatusSuccess, Actual: "1\n"},
+		{Index: 1, Sample: atcoder.Sample{Input: "2\n", Output: "2\n"}, Status: atcoder.StatusError, Err: errors.New("exit status 1")},
+		{Index: 2, Sample: atcoder.Sample{Input: "3\n", Output: "3\n3\n"}, Status: atcoder.StatusFailure, Actual: "3\n4\n"},
+		{Index: 3, Sample: atcoder.Sample{Input: "4\n", Output: "4\n"}, Status: atcoder.StatusFailure, Actual: "5\n"},
+	}
+
+	expected := `ABC051/C: 1/4 FAIL
+sample 1: SUCCESS
+sample 2: ERROR
+sample 3: FAILURE
+sample 4: FAILURE
+diff of sample 3 (-expected +actual):
+@@ -1,2 +1,2 @@
+ 3
+-3
++4
+`
+	if actual := a.resultSummary("https://atcoder.jp/contests/abc051/tasks/abc051_c", results); actual != expected {
+		t.Fatalf("summary wrong.\nwant:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestApp_printJSON(t *testing.T) {
+	var outStream bytes.Buffer
+	a := &App{contest: "abc051", problem: "c", allowFail: map[int]bool{3: true}, outStream: &outStream}
+	results := []atcoder.Result{
+		{Index: 0, Sample: atcoder.Sample{Input: "1\n", Output: "1\n"}, Status: atcoder.StatusSuccess, Actual: "1\n", Elapsed: 1500 * time.Millisecond},
+		{Index: 1, Sample: atcoder.Sample{Input: "2\n", Output: "2\n"}, Status: atcoder.StatusError, Err: errors.New("exit status 1")},
+		{Index: 2, Sample: atcoder.Sample{Input: "3\n", Output: "3\n"}, Status: atcoder.StatusFailure, Actual: "4\n"},
+	}
+	if err := a.printJSON("https://atcoder.jp/contests/abc051/tasks/abc051_c", results); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	var actual jsonResult
+	if err := json.Unmarshal(outStream.Bytes(), &actual); err != nil {
+		t.Fatalf("output should be a JSON document. got: %s", outStream.String())
+	}
+	if actual.Problem != "ABC051/C" || actual.Success || actual.Passed != 1 || actual.Total != 3 || len(actual.Samples) != 3 {
+		t.Fatalf("result wrong. got: %+v", actual)
+	}
+	expected := []jsonSample{
+		{Number: 1, Status: "SUCCESS", Input: "1\n", Expected: "1\n", Actual: "1\n", ElapsedSeconds: 1.5},
+		{Number: 2, Status: "ERROR", Input: "2\n", Expected: "2\n", Error: "exit status 1"},
+		{Number: 3, Status: "FAILURE", AllowedFailure: true, Input: "3\n", Expected: "3\n", Actual: "4\n"},
+	}
+	for i, want := range expected {
+		if actual.Samples[i] != want {
+			t.Fatalf("%d-th sample wrong.\nwant:\n%+v\ngot:\n%+v", i, want, actual.Samples[i])
+		}
+	}
+}
+
+func TestApp_printCachedResult_json(t *testing.T) {
+	cacheDirPath, err := ioutil.TempDir("", "atctest-cache")
+	if err != nil {
+		t.Fatalf("failed to create dummy cache dir: %s", err)
+	}
+	defer os.RemoveAll(cacheDirPath)
+
+	// the label must be escaped as JSON, not as a Go string
+	const problemKey = "saved\"\x01.html"
+	samples := []atcoder.Sample{
+		{Input: "1\n", Output: "1\n"},
+		{Input: "2\n", Output: "2\n"},
+	}
+
+	var outStream bytes.Buffer
+	a := &App{command: "cat", format: formatJSON, resultCache: newResultCache(cacheDirPath, false, atcoder.Options{}), outStream: &outStream}
+	results := []atcoder.Result{
+		{Index: 0, Sample: samples[0], Status: atcoder.StatusSuccess},
+		{Index: 1, Sample: samples[1], Status: atcoder.StatusFailure},
+	}
+	if err := a.resultCache.store(problemKey, a.command, samples, results); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.printCachedResult(problemKey, samples, false); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	var actual jsonResult
+	if err := json.Unmarshal(outStream.Bytes(), &actual); err != nil {
+		t.Fatalf("output should be valid JSON. got: %s\n%s", err, outStream.String())
+	}
+	expected := jsonResult{Problem: problemKey, Success: false, Passed: 1, Total: 2, Unchanged: true, Samples: []jsonSample{}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("document wrong. want=%+v, got=%+v", expected, actual)
+	}
+}
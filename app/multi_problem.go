@@ -0,0 +1,84 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// problemPlaceholder in the command is replaced with the letter of the problem, e.g.) 'python {problem}.py'.
+const problemPlaceholder = "{problem}"
+
+// parseProblems splits the problems given as 'A,B,C'. a single problem is returned as is.
+func parseProblems(problem string) []string {
+	var problems []string
+	for _, p := range strings.Split(problem, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			problems = append(problems, p)
+		}
+	}
+	return problems
+}
+
+func commandFor(command, problem string) string {
+	return strings.Replace(command, problemPlaceholder, problem, -1)
+}
+
+// runProblems runs the samples of each of the problems in turn, and prints the verdicts of them in a table at the end.
+// a problem which cannot be tested, e.g.) not found, does not stop the rest.
+// with -format oneline, only the line of each problem is printed, where the problem which cannot be tested has 'ERROR'.
+func (a *App) runProblems() error {
+	command, buildCommand := a.command, a.buildCommand
+	errs := make([]error, len(a.problems))
+	for i, problem := range a.problems {
+		if a.format != formatOneline {
+			_, _ = fmt.Fprintf(a.outStream, "=== problem %s ===\n", problem)
+		}
+		a.problem = problem
+		a.command = commandFor(command, problem)
+		a.buildCommand = commandFor(buildCommand, problem)
+		errs[i] = a.runProblem()
+		if errs[i] == nil || errors.Is(errs[i], ErrSamplesFailed) {
+			continue
+		}
+		if a.format == formatOneline {
+			_, _ = fmt.Fprintf(a.outStream, "%s: ERROR %s\n", a.problemLabel(""), errs[i])
+		} else {
+			_, _ = fmt.Fprintln(a.errStream, errs[i].Error())
+		}
+	}
+
+	if a.format == formatOneline {
+		for _, err := range errs {
+			if err != nil {
+				return ErrSamplesFailed
+			}
+		}
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(a.outStream, "=== summary ===")
+	failed := 0
+	w := tabwriter.NewWriter(a.outStream, 0, 0, 2, ' ', 0)
+	for i, problem := range a.problems {
+		switch {
+		case errs[i] == nil:
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", problem, verdict(true))
+		case errors.Is(errs[i], ErrSamplesFailed):
+			failed++
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", problem, verdict(false))
+		default:
+			failed++
+			_, _ = fmt.Fprintf(w, "%s\tERROR\t%s\n", problem, errs[i])
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return ErrSamplesFailed
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestParseProblems(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "C", expected: []string{"C"}},
+		{input: "A,B, C", expected: []string{"A", "B", "C"}},
+		{input: "A,,B,", expected: []string{"A", "B"}},
+		{input: "", expected: nil},
+	}
+
+	for _, test := range tests {
+		actual := parseProblems(test.input)
+		if strings.Join(actual, "|") != strings.Join(test.expected, "|") {
+			t.Fatalf("problems of '%s' wrong. want=%v, got=%v", test.input, test.expected, actual)
+		}
+	}
+}
+
+func TestApp_Run_multipleProblems(t *testing.T) {
+	setupHome(t)
+
+	defer gock.Off()
+	for page, fixture := range map[string]string{
+		"^/contests/abc124$":                path.Join("contest", "abc126_not_being_held.html"),
+		"^/contests/abc124/tasks$":          path.Join("problem_list", "abc124.html"),
+		"^/contests/abc124/tasks/abc124_b$": path.Join("problem", "abc124b.html"),
+	} {
+		html, err := ioutil.ReadFile(path.Join("..", "atcoder", "testdata", fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gock.New(baseURL).
+			Get(page).
+			Persist().
+			Reply(http.StatusOK).
+			AddHeader("Content-Type", "text/html").
+			BodyString(string(html))
+	}
+
+	// counts the mountains which are not lower than any mountain before them, only for problem B
+	command := `[ {problem} = B ] && tail -n 1 | tr ' ' '\n' | awk '$1 >= max { count++; max = $1 } END { print count }'`
+	args := []string{"atctest", "-contest", "ABC124", "-problem", "B,Z", "-command", command, "-nocache", "-read-only-cache", "-retries", "0"}
+
+	var outStream, errStream bytes.Buffer
+	a, err := New(args, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != ErrSamplesFailed {
+		t.Fatalf("err wrong. want=%v, got=%v\n%s", ErrSamplesFailed, err, errStream.String())
+	}
+
+	for _, expected := range []string{
+		"=== problem B ===\n",
+		"sample 3: SUCCESS",
+		"=== problem Z ===\n",
+		"=== summary ===\nB  PASS\nZ  ERROR  could not find problem page for problem 'Z'",
+	} {
+		if !strings.Contains(outStream.String(), expected) {
+			t.Fatalf("expect '%s' to contain '%s'", outStream.String(), expected)
+		}
+	}
+}
+
+func TestApp_Run_multipleProblemsOneline(t *testing.T) {
+	setupHome(t)
+
+	defer gock.Off()
+	for page, fixture := range map[string]string{
+		"^/contests/abc124$":                path.Join("contest", "abc126_not_being_held.html"),
+		"^/contests/abc124/tasks$":          path.Join("problem_list", "abc124.html"),
+		"^/contests/abc124/tasks/abc124_b$": path.Join("problem", "abc124b.html"),
+	} {
+		html, err := ioutil.ReadFile(path.Join("..", "atcoder", "testdata", fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gock.New(baseURL).
+			Get(page).
+			Persist().
+			Reply(http.StatusOK).
+			AddHeader("Content-Type", "text/html").
+			BodyString(string(html))
+	}
+
+	command := `tail -n 1 | tr ' ' '\n' | awk '$1 >= max { count++; max = $1 } END { print count }'`
+	args := []string{"atctest", "-contest", "ABC124", "-problem", "B,Z", "-command", command, "-format", "oneline", "-nocache", "-read-only-cache", "-retries", "0"}
+
+	var outStream, errStream bytes.Buffer
+	a, err := New(args, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != ErrSamplesFailed {
+		t.Fatalf("err wrong. want=%v, got=%v\n%s", ErrSamplesFailed, err, errStream.String())
+	}
+
+	expected := "ABC124/B: 3/3 PASS\nABC124/Z: ERROR could not find problem page for problem 'Z' of contest 'ABC124'\n"
+	if outStream.String() != expected {
+		t.Fatalf("output wrong. want=%q, got=%q", expected, outStream.String())
+	}
+}
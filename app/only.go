@@ -0,0 +1,66 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+// selectSamples returns only the samples of the numbers given by -only, keeping their original numbers.
+// it returns an error if any of the numbers is not of the samples.
+func selectSamples(samples []atcoder.Sample, numbers map[int]bool) ([]atcoder.Sample, error) {
+	found := make(map[int]bool)
+	var selected []atcoder.Sample
+	for i, sample := range samples {
+		if sample.Number == 0 {
+			sample.Number = i + 1
+		}
+		found[sample.Number] = true
+		if numbers[sample.Number] {
+			selected = append(selected, sample)
+		}
+	}
+
+	var missing []int
+	for n := range numbers {
+		if !found[n] {
+			missing = append(missing, n)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Ints(missing)
+		return nil, fmt.Errorf("-only: %s not found. the problem has %s", describeNumbers(missing), describeNumbers(sampleNumbers(samples)))
+	}
+	return selected, nil
+}
+
+// sampleNumbers returns the numbers of the samples, which are their positions if they have no numbers.
+func sampleNumbers(samples []atcoder.Sample) []int {
+	numbers := make([]int, len(samples))
+	for i, sample := range samples {
+		numbers[i] = sample.Number
+		if numbers[i] == 0 {
+			numbers[i] = i + 1
+		}
+	}
+	return numbers
+}
+
+// describeNumbers describes the numbers of samples like 'sample 1' or 'samples 1, 2, 3' in a message.
+func describeNumbers(numbers []int) string {
+	if len(numbers) == 1 {
+		return "sample " + joinNumbers(numbers)
+	}
+	return "samples " + joinNumbers(numbers)
+}
+
+func joinNumbers(numbers []int) string {
+	s := make([]string, len(numbers))
+	for i, n := range numbers {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mui87/atctest/atcoder"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestSelectSamples(t *testing.T) {
+	samples := []atcoder.Sample{
+		{Input: "1\n", Output: "1\n"},
+		{Input: "2\n", Output: "2\n"},
+		{Input: "3\n", Output: "3\n"},
+	}
+
+	tests := []struct {
+		name           string
+		inputNumbers   map[int]bool
+		expected       []atcoder.Sample
+		expectedErrMsg string
+	}{
+		{
+			name:         "success-one",
+			inputNumbers: map[int]bool{2: true},
+			expected:     []atcoder.Sample{{Input: "2\n", Output: "2\n", Number: 2}},
+		},
+		{
+			name:         "success-multiple",
+			inputNumbers: map[int]bool{1: true, 3: true},
+			expected: []atcoder.Sample{
+				{Input: "1\n", Output: "1\n", Number: 1},
+				{Input: "3\n", Output: "3\n", Number: 3},
+			},
+		},
+		{
+			name:           "failure-out of range",
+			inputNumbers:   map[int]bool{2: true, 5: true, 4: true},
+			expectedErrMsg: "-only: samples 4, 5 not found. the problem has samples 1, 2, 3",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := selectSamples(samples, test.inputNumbers)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Fatalf("samples wrong. want=%+v, got=%+v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestApp_Run_only(t *testing.T) {
+	setupHome(t)
+
+	pagePath := path.Join("..", "atcoder", "testdata", "problem", "abc124b.html")
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-html-file", pagePath, "-only", "3", "-command", "echo 1"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if !strings.Contains(outStream.String(), "sample 3: SUCCESS") {
+		t.Fatalf("expect '%s' to contain '%s'", outStream.String(), "sample 3: SUCCESS")
+	}
+	if strings.Contains(outStream.String(), "sample 1") {
+		t.Fatalf("only sample 3 should be run. got: %s", outStream.String())
+	}
+}
+
+func TestApp_Run_onlyFetched(t *testing.T) {
+	setupHome(t)
+
+	defer gock.Off()
+	for page, fixture := range map[string]string{
+		"^/contests/abc124$":                path.Join("contest", "abc126_not_being_held.html"),
+		"^/contests/abc124/tasks$":          path.Join("problem_list", "abc124.html"),
+		"^/contests/abc124/tasks/abc124_b$": path.Join("problem", "abc124b.html"),
+	} {
+		html, err := ioutil.ReadFile(path.Join("..", "atcoder", "testdata", fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gock.New(baseURL).
+			Get(page).
+			Persist().
+			Reply(http.StatusOK).
+			AddHeader("Content-Type", "text/html").
+			BodyString(string(html))
+	}
+
+	tests := []struct {
+		name           string
+		inputOnly      string
+		expectedOutput string
+		expectedErrMsg string
+	}{
+		{
+			name:           "success",
+			inputOnly:      "3",
+			expectedOutput: "sample 3: SUCCESS",
+		},
+		{
+			name:           "failure-not found",
+			inputOnly:      "4",
+			expectedErrMsg: "-only: sample 4 not found. the problem has samples 1, 2, 3",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := []string{"atctest", "-contest", "ABC124", "-problem", "B", "-only", test.inputOnly, "-command", "echo 1", "-nocache", "-read-only-cache", "-retries", "0"}
+
+			var outStream, errStream bytes.Buffer
+			a, err := New(args, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			err = a.Run()
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s\n%s", err, errStream.String())
+			}
+			if !strings.Contains(outStream.String(), test.expectedOutput) {
+				t.Fatalf("expect '%s' to contain '%s'", outStream.String(), test.expectedOutput)
+			}
+			if strings.Contains(outStream.String(), "sample 1") {
+				t.Fatalf("only sample 3 should be run. got: %s", outStream.String())
+			}
+		})
+	}
+}
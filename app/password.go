@@ -0,0 +1,36 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// isTerminal and readPassword are replaced in tests, which have no terminal.
+var (
+	isTerminal   = term.IsTerminal
+	readPassword = term.ReadPassword
+)
+
+// promptPassword asks for the password without echo when only the username is given and stdin is a terminal.
+// otherwise the password is left empty, and LogIn fails as without the prompt.
+func (a *App) promptPassword() error {
+	if a.username == "" || a.password != "" {
+		return nil
+	}
+	f, ok := a.inStream.(*os.File)
+	if !ok || !isTerminal(int(f.Fd())) {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(a.errStream, "password for %s: ", a.username)
+	password, err := readPassword(int(f.Fd()))
+	// the newline typed is not echoed either
+	_, _ = fmt.Fprintln(a.errStream)
+	if err != nil {
+		return fmt.Errorf("failed to read the password: %s", err)
+	}
+	a.password = string(password)
+	return nil
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestApp_promptPassword(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputUsername string
+		inputPassword string
+		mockTerminal  bool
+		mockErr       error
+
+		expectedPassword string
+		expectedPrompt   bool
+		expectedErrMsg   string
+	}{
+		{
+			name:             "prompted",
+			inputUsername:    "chokudai",
+			mockTerminal:     true,
+			expectedPassword: "typed",
+			expectedPrompt:   true,
+		},
+		{
+			name:             "password given",
+			inputUsername:    "chokudai",
+			inputPassword:    "given",
+			mockTerminal:     true,
+			expectedPassword: "given",
+		},
+		{
+			name:         "no username",
+			mockTerminal: true,
+		},
+		{
+			name:          "not a terminal",
+			inputUsername: "chokudai",
+		},
+		{
+			name:           "read error",
+			inputUsername:  "chokudai",
+			mockTerminal:   true,
+			mockErr:        errors.New("interrupted"),
+			expectedPrompt: true,
+			expectedErrMsg: "failed to read the password: interrupted",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func(isTerminalOrig func(int) bool, readPasswordOrig func(int) ([]byte, error)) {
+				isTerminal, readPassword = isTerminalOrig, readPasswordOrig
+			}(isTerminal, readPassword)
+			isTerminal = func(int) bool { return test.mockTerminal }
+			readPassword = func(int) ([]byte, error) { return []byte("typed"), test.mockErr }
+
+			var errStream bytes.Buffer
+			a := &App{username: test.inputUsername, password: test.inputPassword, inStream: os.Stdin, errStream: &errStream}
+			err := a.promptPassword()
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				if a.password != test.expectedPassword {
+					t.Fatalf("password wrong. want=%q, got=%q", test.expectedPassword, a.password)
+				}
+			}
+			if prompted := strings.Contains(errStream.String(), "password for chokudai: "); prompted != test.expectedPrompt {
+				t.Fatalf("prompt wrong. want=%t, got: '%s'", test.expectedPrompt, errStream.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/mui87/atctest/commander"
+)
+
+// secretFlags are left out of the printed command so that it can be pasted into a bug report.
+var secretFlags = map[string]bool{
+	"password": true,
+	"cookie":   true,
+	// the url may have the credentials of the proxy
+	"proxy": true,
+}
+
+// commandLine returns a copy-pasteable command line with the flags set explicitly, e.g.) atctest -command 'python c.py' -contest ABC051 -problem C
+func commandLine(flags *flag.FlagSet, resolved map[string]string) string {
+	words := []string{"atctest"}
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "print-command" || secretFlags[f.Name] {
+			return
+		}
+		value := f.Value.String()
+		if v, ok := resolved[f.Name]; ok {
+			value = v
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			if value == "true" {
+				words = append(words, "-"+f.Name)
+			} else {
+				words = append(words, "-"+f.Name+"="+value)
+			}
+			return
+		}
+		words = append(words, "-"+f.Name, commander.Quote(value))
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestApp_Run_printCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputArgs []string
+		expected  string
+	}{
+		{
+			name:      "contest and problem",
+			inputArgs: []string{"atctest", "-print-command", "-problem", "C", "-contest", "ABC051", "-command", "python c.py"},
+			expected:  "atctest -command 'python c.py' -contest ABC051 -problem C\n",
+		},
+		{
+			name:      "url trimmed and bool flags",
+			inputArgs: []string{"atctest", "-url", "'https://atcoder.jp/contests/abc051/tasks/abc051_c'", "-nocache", "-print-command", "-command", "g++ c.cpp && ./a.out"},
+			expected:  "atctest -command 'g++ c.cpp && ./a.out' -nocache -url https://atcoder.jp/contests/abc051/tasks/abc051_c\n",
+		},
+		{
+			name:      "secrets left out",
+			inputArgs: []string{"atctest", "-print-command", "-username", "chokudai", "-password", "pass1234", "-contest", "ABC051", "-problem", "C", "-command", "echo 'hi'"},
+			expected:  "atctest -command 'echo '\\''hi'\\''' -contest ABC051 -problem C -username chokudai\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setupHome(t)
+
+			var outStream, errStream bytes.Buffer
+			a, err := New(test.inputArgs, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if err := a.Run(); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if outStream.String() != test.expected {
+				t.Fatalf("command line wrong.\nwant: %s\ngot:  %s", test.expected, outStream.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+const resultCacheDirName = "results"
+
+type resultRecord struct {
+	SamplesHash string `json:"samples_hash"`
+	// OptionsHash is the hash of the options which change the verdict, e.g.) -tolerance or -checker.
+	OptionsHash string           `json:"options_hash"`
+	FileMtimes  map[string]int64 `json:"file_mtimes"`
+	Success     bool             `json:"success"`
+	// Failed is the numbers of the samples which did not pass.
+	Failed []int `json:"failed"`
+}
+
+// resultCache stores the result of the last run for each pair of problem and command.
+type resultCache struct {
+	dirPath  string
+	readOnly bool
+	// optionsHash is the hash of the options of the run, see hashOptions.
+	optionsHash string
+	// checker is the command of -checker, whose referenced files are also watched like those of the command.
+	checker string
+}
+
+func newResultCache(cacheDirPath string, readOnly bool, opts atcoder.Options) *resultCache {
+	if cacheDirPath == "" {
+		return &resultCache{}
+	}
+	return &resultCache{
+		dirPath:     path.Join(cacheDirPath, resultCacheDirName),
+		readOnly:    readOnly,
+		optionsHash: hashOptions(opts),
+		checker:     opts.Checker,
+	}
+}
+
+// lookup returns the verdict of the last run if neither the samples, the command nor the files referenced by the command have changed since then.
+// the failures of the samples in allowFail do not fail the verdict, as in the run itself with -allow-fail.
+func (r *resultCache) lookup(problemKey, command string, samples []atcoder.Sample, allowFail map[int]bool) (bool, bool) {
+	record, ok := r.load(problemKey, command, samples)
+	if !ok {
+		return false, false
+	}
+
+	mtimes := referencedFileMtimes(r.watchedCommands(command))
+	if len(mtimes) != len(record.FileMtimes) {
+		return false, false
+	}
+	for file, mtime := range mtimes {
+		if record.FileMtimes[file] != mtime {
+			return false, false
+		}
+	}
+
+	if record.Failed == nil {
+		// the records of older versions have only the verdict
+		return record.Success, true
+	}
+	for _, n := range record.Failed {
+		if !allowFail[n] {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+// failedNumbers returns the numbers of the samples which failed in the last run for the same samples and command.
+func (r *resultCache) failedNumbers(problemKey, command string, samples []atcoder.Sample) ([]int, bool) {
+	record, ok := r.load(problemKey, command, samples)
+	if !ok {
+		return nil, false
+	}
+	return record.Failed, true
+}
+
+func (r *resultCache) load(problemKey, command string, samples []atcoder.Sample) (*resultRecord, bool) {
+	if r.dirPath == "" {
+		return nil, false
+	}
+
+	bytes, err := ioutil.ReadFile(r.filePath(problemKey, command))
+	if err != nil {
+		return nil, false
+	}
+
+	var record resultRecord
+	if err := json.Unmarshal(bytes, &record); err != nil {
+		return nil, false
+	}
+	if record.SamplesHash != hashSamples(samples) || record.OptionsHash != r.optionsHash {
+		return nil, false
+	}
+
+	return &record, true
+}
+
+// store records the results for the samples. results may be for a part of the samples, e.g.) when rerunning failed ones.
+func (r *resultCache) store(problemKey, command string, samples []atcoder.Sample, results []atcoder.Result) error {
+	if r.dirPath == "" || r.readOnly {
+		return nil
+	}
+
+	if err := os.MkdirAll(r.dirPath, 0777); err != nil {
+		return err
+	}
+
+	record := resultRecord{
+		SamplesHash: hashSamples(samples),
+		OptionsHash: r.optionsHash,
+		FileMtimes:  referencedFileMtimes(r.watchedCommands(command)),
+		Success:     true,
+		Failed:      []int{},
+	}
+	for _, result := range results {
+		if !result.Success() {
+			record.Success = false
+			record.Failed = append(record.Failed, result.Number())
+		}
+	}
+
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.filePath(problemKey, command), bytes, 0644)
+}
+
+func (r *resultCache) filePath(problemKey, command string) string {
+	sum := sha256.Sum256([]byte(problemKey + "\x00" + command))
+	return path.Join(r.dirPath, hex.EncodeToString(sum[:])+".json")
+}
+
+// watchedCommands returns the commands whose referenced files invalidate the result when they are modified.
+func (r *resultCache) watchedCommands(command string) string {
+	if r.checker == "" {
+		return command
+	}
+	return command + " ; " + r.checker
+}
+
+// hashOptions returns the hash of the options which may change the verdict.
+// the options which only change how the results are shown or when the samples are run, e.g.) -diff or -jobs, are left out,
+// and so is the deadline, which differs in every run.
+func hashOptions(opts atcoder.Options) string {
+	ignoreLines := ""
+	if opts.IgnoreLines != nil {
+		ignoreLines = opts.IgnoreLines.String()
+	}
+	opts.IgnoreLines = nil
+	opts.Deadline = time.Time{}
+	opts.WarnSlow = 0
+	opts.ShowDiff, opts.DiffMaxLines, opts.HexDump = false, 0, false
+	opts.DelayBetweenSamples, opts.FailFast, opts.Jobs = 0, false, 0
+
+	bytes, _ := json.Marshal(struct {
+		Options     atcoder.Options
+		IgnoreLines string
+	}{Options: opts, IgnoreLines: ignoreLines})
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:])
+}
+
+func hashSamples(samples []atcoder.Sample) string {
+	bytes, _ := json.Marshal(samples)
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// referencedFileMtimes returns modification times of the files which appear as words in the command, e.g. c.py of 'python c.py'.
+func referencedFileMtimes(command string) map[string]int64 {
+	mtimes := make(map[string]int64)
+	words := strings.FieldsFunc(command, func(r rune) bool {
+		return strings.ContainsRune(" \t\n;&|<>()'\"`", r)
+	})
+	for _, word := range words {
+		info, err := os.Stat(word)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		mtimes[word] = info.ModTime().UnixNano()
+	}
+	return mtimes
+}
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+func TestResultCache(t *testing.T) {
+	cacheDirPath, err := ioutil.TempDir("", "atctest-cache")
+	if err != nil {
+		t.Fatalf("failed to create dummy cache dir: %s", err)
+	}
+	defer os.RemoveAll(cacheDirPath)
+
+	solution := path.Join(cacheDirPath, "c.py")
+	if err := ioutil.WriteFile(solution, []byte("print(1)"), 0644); err != nil {
+		t.Fatalf("failed to write dummy solution: %s", err)
+	}
+
+	const problemKey = "https://atcoder.jp/contests/abc051/tasks/abc051_c"
+	command := "python " + solution
+	samples := []atcoder.Sample{{Input: "1\n", Output: "1\n"}}
+
+	r := newResultCache(cacheDirPath, false, atcoder.Options{})
+	if _, ok := r.lookup(problemKey, command, samples, nil); ok {
+		t.Fatal("lookup should miss before store")
+	}
+
+	results := []atcoder.Result{{Index: 0, Sample: samples[0], Status: atcoder.StatusSuccess}}
+	if err := r.store(problemKey, command, samples, results); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	success, ok := r.lookup(problemKey, command, samples, nil)
+	if !ok || !success {
+		t.Fatalf("lookup wrong. want=(true, true), got=(%t, %t)", success, ok)
+	}
+
+	if _, ok := r.lookup(problemKey, command+" --debug", samples, nil); ok {
+		t.Fatal("lookup should miss when the command changed")
+	}
+	if _, ok := r.lookup(problemKey, command, []atcoder.Sample{{Input: "2\n", Output: "2\n"}}, nil); ok {
+		t.Fatal("lookup should miss when the samples changed")
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(solution, later, later); err != nil {
+		t.Fatalf("failed to touch dummy solution: %s", err)
+	}
+	if _, ok := r.lookup(problemKey, command, samples, nil); ok {
+		t.Fatal("lookup should miss when the solution file changed")
+	}
+}
+
+func TestResultCache_options(t *testing.T) {
+	cacheDirPath, err := ioutil.TempDir("", "atctest-cache")
+	if err != nil {
+		t.Fatalf("failed to create dummy cache dir: %s", err)
+	}
+	defer os.RemoveAll(cacheDirPath)
+
+	judge := path.Join(cacheDirPath, "judge.py")
+	if err := ioutil.WriteFile(judge, []byte("exit(0)"), 0644); err != nil {
+		t.Fatalf("failed to write dummy checker: %s", err)
+	}
+
+	const problemKey = "https://atcoder.jp/contests/abc051/tasks/abc051_c"
+	const command = "python c.py"
+	samples := []atcoder.Sample{{Input: "1\n", Output: "1\n"}}
+	opts := atcoder.Options{Timeout: 2 * time.Second, Checker: "python " + judge}
+
+	results := []atcoder.Result{{Index: 0, Sample: samples[0], Status: atcoder.StatusFailure}}
+	if err := newResultCache(cacheDirPath, false, opts).store(problemKey, command, samples, results); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	tests := []struct {
+		name        string
+		inputOpts   func(atcoder.Options) atcoder.Options
+		expectedHit bool
+	}{
+		{
+			name:        "same options",
+			inputOpts:   func(o atcoder.Options) atcoder.Options { return o },
+			expectedHit: true,
+		},
+		{
+			name: "options not changing the verdict",
+			inputOpts: func(o atcoder.Options) atcoder.Options {
+				o.Jobs, o.ShowDiff, o.Deadline = 4, true, time.Now()
+				return o
+			},
+			expectedHit: true,
+		},
+		{
+			name:      "tolerance",
+			inputOpts: func(o atcoder.Options) atcoder.Options { o.Tolerance = 1e-6; return o },
+		},
+		{
+			name:      "strict",
+			inputOpts: func(o atcoder.Options) atcoder.Options { o.Strict = true; return o },
+		},
+		{
+			name:      "timeout",
+			inputOpts: func(o atcoder.Options) atcoder.Options { o.Timeout = 5 * time.Second; return o },
+		},
+		{
+			name:      "checker",
+			inputOpts: func(o atcoder.Options) atcoder.Options { o.Checker = ""; return o },
+		},
+		{
+			name: "ignore-lines",
+			inputOpts: func(o atcoder.Options) atcoder.Options {
+				o.IgnoreLines = regexp.MustCompile("^DEBUG")
+				return o
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newResultCache(cacheDirPath, false, test.inputOpts(opts))
+			if _, ok := r.lookup(problemKey, command, samples, nil); ok != test.expectedHit {
+				t.Fatalf("hit wrong. want=%t, got=%t", test.expectedHit, ok)
+			}
+		})
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(judge, later, later); err != nil {
+		t.Fatalf("failed to touch dummy checker: %s", err)
+	}
+	if _, ok := newResultCache(cacheDirPath, false, opts).lookup(problemKey, command, samples, nil); ok {
+		t.Fatal("lookup should miss when the checker file changed")
+	}
+}
+
+func TestResultCache_allowFail(t *testing.T) {
+	cacheDirPath, err := ioutil.TempDir("", "atctest-cache")
+	if err != nil {
+		t.Fatalf("failed to create dummy cache dir: %s", err)
+	}
+	defer os.RemoveAll(cacheDirPath)
+
+	const problemKey = "https://atcoder.jp/contests/abc051/tasks/abc051_c"
+	const command = "python c.py"
+	samples := []atcoder.Sample{
+		{Input: "1\n", Output: "1\n"},
+		{Input: "2\n", Output: "2\n"},
+	}
+
+	r := newResultCache(cacheDirPath, false, atcoder.Options{})
+	results := []atcoder.Result{
+		{Index: 0, Sample: samples[0], Status: atcoder.StatusSuccess},
+		{Index: 1, Sample: samples[1], Status: atcoder.StatusFailure},
+	}
+	if err := r.store(problemKey, command, samples, results); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	tests := []struct {
+		name            string
+		inputAllowFail  map[int]bool
+		expectedSuccess bool
+	}{
+		{name: "not allowed", inputAllowFail: nil, expectedSuccess: false},
+		{name: "allowed", inputAllowFail: map[int]bool{2: true}, expectedSuccess: true},
+		{name: "another allowed", inputAllowFail: map[int]bool{1: true}, expectedSuccess: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			success, ok := r.lookup(problemKey, command, samples, test.inputAllowFail)
+			if !ok {
+				t.Fatal("lookup should hit after store")
+			}
+			if success != test.expectedSuccess {
+				t.Fatalf("success wrong. want=%t, got=%t", test.expectedSuccess, success)
+			}
+		})
+	}
+}
+
+func TestResultCache_failedNumbers(t *testing.T) {
+	cacheDirPath, err := ioutil.TempDir("", "atctest-cache")
+	if err != nil {
+		t.Fatalf("failed to create dummy cache dir: %s", err)
+	}
+	defer os.RemoveAll(cacheDirPath)
+
+	const problemKey = "https://atcoder.jp/contests/abc051/tasks/abc051_c"
+	const command = "python c.py"
+	samples := []atcoder.Sample{
+		{Input: "1\n", Output: "1\n"},
+		{Input: "2\n", Output: "2\n"},
+		{Input: "3\n", Output: "3\n"},
+	}
+
+	r := newResultCache(cacheDirPath, false, atcoder.Options{})
+	if _, ok := r.failedNumbers(problemKey, command, samples); ok {
+		t.Fatal("failedNumbers should miss before store")
+	}
+
+	results := []atcoder.Result{
+		{Index: 0, Sample: samples[0], Status: atcoder.StatusSuccess},
+		{Index: 1, Sample: samples[1], Status: atcoder.StatusFailure},
+		{Index: 2, Sample: samples[2], Status: atcoder.StatusError},
+	}
+	if err := r.store(problemKey, command, samples, results); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	failed, ok := r.failedNumbers(problemKey, command, samples)
+	if !ok {
+		t.Fatal("failedNumbers should hit after store")
+	}
+	if len(failed) != 2 || failed[0] != 2 || failed[1] != 3 {
+		t.Fatalf("failed numbers wrong. want=[2 3], got=%v", failed)
+	}
+}
+
+func TestApp_failedSamples(t *testing.T) {
+	cacheDirPath, err := ioutil.TempDir("", "atctest-cache")
+	if err != nil {
+		t.Fatalf("failed to create dummy cache dir: %s", err)
+	}
+	defer os.RemoveAll(cacheDirPath)
+
+	const problemKey = "https://atcoder.jp/contests/abc051/tasks/abc051_c"
+	samples := []atcoder.Sample{
+		{Input: "1\n", Output: "1\n"},
+		{Input: "2\n", Output: "2\n"},
+		{Input: "3\n", Output: "3\n"},
+	}
+
+	var errStream bytes.Buffer
+	a := &App{command: "python c.py", resultCache: newResultCache(cacheDirPath, false, atcoder.Options{}), errStream: &errStream}
+
+	if targets := a.failedSamples(problemKey, samples); len(targets) != len(samples) {
+		t.Fatalf("all samples should be run without the last result. got: %+v", targets)
+	}
+
+	results := []atcoder.Result{
+		{Index: 0, Sample: samples[0], Status: atcoder.StatusSuccess},
+		{Index: 1, Sample: samples[1], Status: atcoder.StatusSuccess},
+		{Index: 2, Sample: samples[2], Status: atcoder.StatusFailure},
+	}
+	if err := a.resultCache.store(problemKey, a.command, samples, results); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	targets := a.failedSamples(problemKey, samples)
+	expected := atcoder.Sample{Input: "3\n", Output: "3\n", Number: 3}
+	if len(targets) != 1 || targets[0] != expected {
+		t.Fatalf("failed samples wrong. want=[%+v], got=%+v", expected, targets)
+	}
+}
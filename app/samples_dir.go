@@ -0,0 +1,78 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+const (
+	sampleInputExt  = ".in"
+	sampleOutputExt = ".out"
+)
+
+// readSamplesDir reads the samples from the pairs of files named by their numbers like 1.in and 1.out in the directory.
+// the samples are sorted by their numbers, which are kept as the numbers of the samples.
+func readSamplesDir(dirPath string) ([]atcoder.Sample, error) {
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the samples directory: %s", err)
+	}
+
+	var numbers []int
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != sampleInputExt {
+			continue
+		}
+		name := strings.TrimSuffix(f.Name(), sampleInputExt)
+		n, err := strconv.Atoi(name)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("the input file must be named by the number of the sample like 1%s. got: %s", sampleInputExt, f.Name())
+		}
+		numbers = append(numbers, n)
+	}
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("no samples found in %s. put the pairs of files like 1%s and 1%s", dirPath, sampleInputExt, sampleOutputExt)
+	}
+	sort.Ints(numbers)
+
+	var samples []atcoder.Sample
+	for _, n := range numbers {
+		input, err := ioutil.ReadFile(filepath.Join(dirPath, strconv.Itoa(n)+sampleInputExt))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the input of sample %d: %s", n, err)
+		}
+		output, err := ioutil.ReadFile(filepath.Join(dirPath, strconv.Itoa(n)+sampleOutputExt))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the output of sample %d: %s", n, err)
+		}
+		samples = append(samples, atcoder.Sample{Input: string(input), Output: string(output), Number: n})
+	}
+	return samples, nil
+}
+
+// writeSamplesDir writes the samples to the pairs of files in the format readSamplesDir reads.
+func writeSamplesDir(dirPath string, samples []atcoder.Sample) error {
+	if err := os.MkdirAll(dirPath, 0777); err != nil {
+		return fmt.Errorf("failed to create the directory to dump the samples: %s", err)
+	}
+	for i, sample := range samples {
+		n := sample.Number
+		if n == 0 {
+			n = i + 1
+		}
+		if err := ioutil.WriteFile(filepath.Join(dirPath, strconv.Itoa(n)+sampleInputExt), []byte(sample.Input), 0644); err != nil {
+			return fmt.Errorf("failed to dump the input of sample %d: %s", n, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dirPath, strconv.Itoa(n)+sampleOutputExt), []byte(sample.Output), 0644); err != nil {
+			return fmt.Errorf("failed to dump the output of sample %d: %s", n, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+func TestReadSamplesDir(t *testing.T) {
+	tests := []struct {
+		name string
+
+		inputFiles map[string]string
+
+		expectedSamples []atcoder.Sample
+		expectedErrMsg  string
+	}{
+		{
+			name: "success-sorted_by_number",
+			inputFiles: map[string]string{
+				"10.in":     "5 5\n",
+				"10.out":    "10\n",
+				"2.in":      "1 2\n",
+				"2.out":     "3\n",
+				"README.md": "edge cases",
+			},
+			expectedSamples: []atcoder.Sample{
+				{Input: "1 2\n", Output: "3\n", Number: 2},
+				{Input: "5 5\n", Output: "10\n", Number: 10},
+			},
+		},
+		{
+			name:           "failure-output_missing",
+			inputFiles:     map[string]string{"1.in": "1 2\n"},
+			expectedErrMsg: "failed to read the output of sample 1",
+		},
+		{
+			name:           "failure-not_numbered",
+			inputFiles:     map[string]string{"edge.in": "0 0\n", "edge.out": "0\n"},
+			expectedErrMsg: "must be named by the number of the sample like 1.in. got: edge.in",
+		},
+		{
+			name:           "failure-empty",
+			inputFiles:     map[string]string{},
+			expectedErrMsg: "no samples found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "atctest-samples")
+			if err != nil {
+				t.Fatalf("failed to create samples dir: %s", err)
+			}
+			defer func() {
+				_ = os.RemoveAll(dir)
+			}()
+			for name, content := range test.inputFiles {
+				if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write %s: %s", name, err)
+				}
+			}
+
+			samples, err := readSamplesDir(dir)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if len(samples) != len(test.expectedSamples) {
+				t.Fatalf("length of samples wrong. want=%d, got=%d", len(test.expectedSamples), len(samples))
+			}
+			for i, expected := range test.expectedSamples {
+				if samples[i] != expected {
+					t.Fatalf("%d-th sample wrong. want=%+v, got=%+v", i, expected, samples[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteSamplesDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atctest-dump")
+	if err != nil {
+		t.Fatalf("failed to create dump dir: %s", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	samples := []atcoder.Sample{
+		{Input: "1 2\n", Output: "3\n"},
+		{Input: "5 5\n", Output: "10\n", Number: 3},
+	}
+	dumpDir := filepath.Join(dir, "cases")
+	if err := writeSamplesDir(dumpDir, samples); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	// the samples are read back as they are, numbered
+	actual, err := readSamplesDir(dumpDir)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	expected := []atcoder.Sample{
+		{Input: "1 2\n", Output: "3\n", Number: 1},
+		{Input: "5 5\n", Output: "10\n", Number: 3},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("length of samples wrong. want=%d, got=%d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("%d-th sample wrong. want=%+v, got=%+v", i, expected[i], actual[i])
+		}
+	}
+}
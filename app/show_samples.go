@@ -0,0 +1,36 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+// printSamples prints the input and expected output of each sample, for checking the samples are taken correctly.
+func printSamples(w io.Writer, samples []atcoder.Sample) {
+	for i, sample := range samples {
+		n := sample.Number
+		if n == 0 {
+			n = i + 1
+		}
+		_, _ = fmt.Fprintf(w, "=== sample %d ===\n", n)
+		_, _ = fmt.Fprintln(w, "input:")
+		writeSampleText(w, sample.Input)
+		_, _ = fmt.Fprintln(w, "expected output:")
+		writeSampleText(w, sample.Output)
+	}
+}
+
+// writeSampleText writes the text ending it with a newline, marking the empty one so that it is not mistaken for a blank line.
+func writeSampleText(w io.Writer, text string) {
+	if text == "" {
+		_, _ = fmt.Fprintln(w, "(empty)")
+		return
+	}
+	_, _ = fmt.Fprint(w, text)
+	if !strings.HasSuffix(text, "\n") {
+		_, _ = fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"bytes"
+	"path"
+	"testing"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+func TestPrintSamples(t *testing.T) {
+	var buf bytes.Buffer
+	printSamples(&buf, []atcoder.Sample{
+		{Input: "1 2\n", Output: "3\n"},
+		{Input: "", Output: "0", Number: 3},
+	})
+
+	expected := "=== sample 1 ===\ninput:\n1 2\nexpected output:\n3\n" +
+		"=== sample 3 ===\ninput:\n(empty)\nexpected output:\n0\n"
+	if buf.String() != expected {
+		t.Fatalf("output wrong. want=%q, got=%q", expected, buf.String())
+	}
+}
+
+func TestApp_Run_showSamples(t *testing.T) {
+	setupHome(t)
+
+	pagePath := path.Join("..", "atcoder", "testdata", "problem", "abc124b.html")
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-html-file", pagePath, "-show-samples"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	expected := "=== sample 3 ===\ninput:\n5\n9 5 6 8 4\nexpected output:\n1\n"
+	if !bytes.HasSuffix(outStream.Bytes(), []byte(expected)) {
+		t.Fatalf("expect '%s' to end with '%s'", outStream.String(), expected)
+	}
+}
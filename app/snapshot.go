@@ -0,0 +1,85 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+// snapshotEntry is the output of your program for a sample, recorded to be compared with in later runs.
+type snapshotEntry struct {
+	Number int    `json:"number"`
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+// checkSnapshot compares the outputs of your program with the snapshot file, independently of the expected outputs.
+// the outputs are recorded to the file if it does not exist yet.
+func (a *App) checkSnapshot(results []atcoder.Result) error {
+	bytes, err := ioutil.ReadFile(a.snapshotPath)
+	if os.IsNotExist(err) {
+		return a.recordSnapshot(results)
+	} else if err != nil {
+		return fmt.Errorf("failed to read the snapshot: %s", err)
+	}
+
+	var entries []snapshotEntry
+	if err := json.Unmarshal(bytes, &entries); err != nil {
+		return fmt.Errorf("failed to parse the snapshot %s: %s", a.snapshotPath, err)
+	}
+	recorded := make(map[int]snapshotEntry)
+	for _, entry := range entries {
+		recorded[entry.Number] = entry
+	}
+
+	changed := 0
+	for _, result := range results {
+		if result.Status == atcoder.StatusError || result.Status == atcoder.StatusTimeout || result.Status == atcoder.StatusRuntimeError {
+			continue
+		}
+		entry, ok := recorded[result.Number()]
+		if !ok || entry.Input != result.Sample.Input {
+			// the sample did not exist when the snapshot was recorded
+			continue
+		}
+		if entry.Output == result.Actual {
+			continue
+		}
+		changed++
+		_, _ = fmt.Fprintf(a.outStream, "[SNAPSHOT] output of sample %d changed (-snapshot +actual):\n", result.Number())
+		_, _ = fmt.Fprint(a.outStream, atcoder.Diff(entry.Output, result.Actual, atcoder.DiffOptions{Context: 3}))
+	}
+
+	if changed > 0 {
+		return fmt.Errorf("outputs of %d samples differ from the snapshot %s. remove it to record the current outputs", changed, a.snapshotPath)
+	}
+	return nil
+}
+
+func (a *App) recordSnapshot(results []atcoder.Result) error {
+	var entries []snapshotEntry
+	for _, result := range results {
+		if result.Status == atcoder.StatusError || result.Status == atcoder.StatusTimeout || result.Status == atcoder.StatusRuntimeError {
+			continue
+		}
+		entries = append(entries, snapshotEntry{
+			Number: result.Number(),
+			Input:  result.Sample.Input,
+			Output: result.Actual,
+		})
+	}
+
+	bytes, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(a.snapshotPath, bytes, 0644); err != nil {
+		return fmt.Errorf("failed to record the snapshot: %s", err)
+	}
+
+	_, _ = fmt.Fprintf(a.outStream, "outputs of %d samples are recorded to %s\n", len(entries), a.snapshotPath)
+	return nil
+}
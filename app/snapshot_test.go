@@ -0,0 +1,63 @@
+package app
+
+import (
+	"bytes"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestApp_Run_snapshot(t *testing.T) {
+	home := setupHome(t)
+	snapshotPath := path.Join(home, "c.snapshot.json")
+
+	tests := []struct {
+		name              string
+		inputCommand      string
+		inputExpected     string
+		expectedErr       bool
+		expectedOutStream string
+	}{
+		{
+			name:              "record",
+			inputCommand:      "cat",
+			inputExpected:     "1",
+			expectedOutStream: "outputs of 1 samples are recorded to " + snapshotPath,
+		},
+		{
+			name:              "unchanged",
+			inputCommand:      "cat",
+			inputExpected:     "1",
+			expectedOutStream: "sample 1: SUCCESS",
+		},
+		{
+			name:              "changed-while-expected-output-passes",
+			inputCommand:      "echo 2",
+			inputExpected:     "2",
+			expectedErr:       true,
+			expectedOutStream: "[SNAPSHOT] output of sample 1 changed (-snapshot +actual):\n@@ -1,1 +1,1 @@\n-1\n+2\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream, errStream bytes.Buffer
+			args := []string{"atctest", "-snapshot", snapshotPath, "-input", "1", "-expected", test.inputExpected, "-command", test.inputCommand}
+			a, err := New(args, &outStream, &errStream)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			err = a.Run()
+			if test.expectedErr && err == nil {
+				t.Fatal("err should not be nil. got: nil")
+			}
+			if !test.expectedErr && err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if !strings.Contains(outStream.String(), test.expectedOutStream) {
+				t.Fatalf("expect '%s' to contain '%s'", outStream.String(), test.expectedOutStream)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+// envState is the environment variable for the path of the file which each run appends its result to.
+const envState = "ATCTEST_STATE"
+
+type stateEntry struct {
+	Time    time.Time `json:"time"`
+	Problem string    `json:"problem"`
+	Command string    `json:"command"`
+	Passed  int       `json:"passed"`
+	Total   int       `json:"total"`
+	// Success is whether the run passed, where the failures allowed by -allow-fail do not count.
+	// it is nil in the entries recorded by older versions of atctest.
+	Success *bool `json:"success,omitempty"`
+}
+
+func (e stateEntry) success() bool {
+	if e.Success != nil {
+		return *e.Success
+	}
+	return e.Passed == e.Total
+}
+
+// appendState appends the results of the run to the state file as a JSON line.
+func appendState(stateFilePath, problem, command string, results []atcoder.Result, success bool) error {
+	entry := stateEntry{
+		Time:    time.Now(),
+		Problem: problem,
+		Command: command,
+		Total:   len(results),
+		Success: &success,
+	}
+	for _, result := range results {
+		if result.Success() {
+			entry.Passed++
+		}
+	}
+
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(stateFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(bytes, '\n'))
+	return err
+}
+
+func readState(stateFilePath string) ([]stateEntry, error) {
+	f, err := os.Open(stateFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %s", err)
+	}
+	defer f.Close()
+
+	var entries []stateEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry stateEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse state file %s: %s", stateFilePath, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read state file: %s", err)
+	}
+
+	return entries, nil
+}
+
+// printSummary prints the latest result of each problem recorded in the state file.
+func (a *App) printSummary() error {
+	stateFilePath := os.Getenv(envState)
+	if stateFilePath == "" {
+		return errors.New("set " + envState + " to the path of the state file to summarize")
+	}
+
+	entries, err := readState(stateFilePath)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	latest := make(map[string]stateEntry)
+	for _, entry := range entries {
+		if _, ok := latest[entry.Problem]; !ok {
+			problems = append(problems, entry.Problem)
+		}
+		latest[entry.Problem] = entry
+	}
+
+	passed := 0
+	for _, problem := range problems {
+		entry := latest[problem]
+		if entry.success() {
+			passed++
+		}
+		_, _ = fmt.Fprintf(a.outStream, "%s: %d/%d %s\n", problem, entry.Passed, entry.Total, verdict(entry.success()))
+	}
+	_, _ = fmt.Fprintf(a.outStream, "%d/%d problems passed in %d runs\n", passed, len(problems), len(entries))
+
+	if passed != len(problems) {
+		return ErrSamplesFailed
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestApp_Run_summary(t *testing.T) {
+	setupHome(t)
+
+	stateDirPath, err := ioutil.TempDir("", "atctest-state")
+	if err != nil {
+		t.Fatalf("failed to create dummy state dir: %s", err)
+	}
+	defer os.RemoveAll(stateDirPath)
+	t.Setenv(envState, path.Join(stateDirPath, "state.jsonl"))
+
+	runs := [][]string{
+		{"atctest", "-input", "1", "-expected", "2", "-command", "cat"},
+		{"atctest", "-input", "1", "-expected", "1", "-command", "cat"},
+		{"atctest", "-url", "https://atcoder.jp/contests/abc051/tasks/abc051_c", "-input", "1", "-expected", "2", "-command", "cat"},
+	}
+	for _, args := range runs {
+		var outStream, errStream bytes.Buffer
+		a, err := New(args, &outStream, &errStream)
+		if err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+		_ = a.Run()
+	}
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-summary"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != ErrSamplesFailed {
+		t.Fatalf("err wrong. want=%v, got=%v", ErrSamplesFailed, err)
+	}
+
+	expected := "inline: 1/1 PASS\nabc051_c: 0/1 FAIL\n1/2 problems passed in 3 runs\n"
+	if outStream.String() != expected {
+		t.Fatalf("summary wrong.\nwant:\n%s\ngot:\n%s", expected, outStream.String())
+	}
+}
+
+func TestApp_Run_summary_allowFail(t *testing.T) {
+	setupHome(t)
+
+	stateDirPath, err := ioutil.TempDir("", "atctest-state")
+	if err != nil {
+		t.Fatalf("failed to create dummy state dir: %s", err)
+	}
+	defer os.RemoveAll(stateDirPath)
+	statePath := path.Join(stateDirPath, "state.jsonl")
+	t.Setenv(envState, statePath)
+
+	// an entry recorded by an older version, without success
+	if err := ioutil.WriteFile(statePath, []byte(`{"problem":"abc051_c","passed":0,"total":1}`+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write dummy state file: %s", err)
+	}
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-input", "1", "-expected", "2", "-allow-fail", "1", "-command", "cat"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	outStream.Reset()
+	a, err = New([]string{"atctest", "-summary"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != ErrSamplesFailed {
+		t.Fatalf("err wrong. want=%v, got=%v", ErrSamplesFailed, err)
+	}
+
+	expected := "abc051_c: 0/1 FAIL\ninline: 0/1 PASS\n1/2 problems passed in 2 runs\n"
+	if outStream.String() != expected {
+		t.Fatalf("summary wrong.\nwant:\n%s\ngot:\n%s", expected, outStream.String())
+	}
+}
+
+func TestApp_Run_summary_withoutState(t *testing.T) {
+	t.Setenv(envState, "")
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-summary"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err == nil {
+		t.Fatal("err should not be nil. got: nil")
+	}
+}
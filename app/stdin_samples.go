@@ -0,0 +1,67 @@
+package app
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+const (
+	stdinInputDelimiter  = "---INPUT---"
+	stdinOutputDelimiter = "---OUTPUT---"
+)
+
+// parseStdinSamples parses the samples given in the format below. blank lines before the first delimiter are ignored.
+//
+//	---INPUT---
+//	1 2
+//	---OUTPUT---
+//	3
+//	---INPUT---
+//	...
+func parseStdinSamples(r io.Reader) ([]atcoder.Sample, error) {
+	var samples []atcoder.Sample
+	inOutput := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		switch {
+		case line == stdinInputDelimiter:
+			if len(samples) > 0 && !inOutput {
+				return nil, fmt.Errorf("line %d: sample %d has no %s", lineNumber, len(samples), stdinOutputDelimiter)
+			}
+			samples = append(samples, atcoder.Sample{})
+			inOutput = false
+		case line == stdinOutputDelimiter:
+			if len(samples) == 0 || inOutput {
+				return nil, fmt.Errorf("line %d: %s must follow %s", lineNumber, stdinOutputDelimiter, stdinInputDelimiter)
+			}
+			inOutput = true
+		case len(samples) == 0:
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("line %d: samples must start with %s", lineNumber, stdinInputDelimiter)
+			}
+		case inOutput:
+			samples[len(samples)-1].Output += line + "\n"
+		default:
+			samples[len(samples)-1].Input += line + "\n"
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read samples from stdin: %s", err)
+	}
+
+	if len(samples) == 0 {
+		return nil, errors.New("no samples given from stdin")
+	}
+	if !inOutput {
+		return nil, fmt.Errorf("sample %d has no %s", len(samples), stdinOutputDelimiter)
+	}
+	return samples, nil
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+func TestParseStdinSamples(t *testing.T) {
+	tests := []struct {
+		name string
+
+		input string
+
+		expectedSamples []atcoder.Sample
+		expectedErrMsg  string
+	}{
+		{
+			name:  "success-single_sample",
+			input: "---INPUT---\n1 2\n---OUTPUT---\n3\n",
+			expectedSamples: []atcoder.Sample{
+				{Input: "1 2\n", Output: "3\n"},
+			},
+		},
+		{
+			name:  "success-multiple_samples",
+			input: "\n---INPUT---\n2\n1 2\n---OUTPUT---\n3\n---INPUT---\n1\n5\n---OUTPUT---\n5",
+			expectedSamples: []atcoder.Sample{
+				{Input: "2\n1 2\n", Output: "3\n"},
+				{Input: "1\n5\n", Output: "5\n"},
+			},
+		},
+		{
+			name:  "success-crlf",
+			input: "---INPUT---\r\n1 2\r\n---OUTPUT---\r\n3\r\n",
+			expectedSamples: []atcoder.Sample{
+				{Input: "1 2\n", Output: "3\n"},
+			},
+		},
+		{
+			name:           "failure-empty",
+			input:          "",
+			expectedErrMsg: "no samples given from stdin",
+		},
+		{
+			name:           "failure-text_before_input",
+			input:          "hello\n---INPUT---\n1\n---OUTPUT---\n1\n",
+			expectedErrMsg: "line 1: samples must start with ---INPUT---",
+		},
+		{
+			name:           "failure-output_missing",
+			input:          "---INPUT---\n1\n---INPUT---\n2\n---OUTPUT---\n2\n",
+			expectedErrMsg: "line 3: sample 1 has no ---OUTPUT---",
+		},
+		{
+			name:           "failure-last_output_missing",
+			input:          "---INPUT---\n1\n---OUTPUT---\n1\n---INPUT---\n2\n",
+			expectedErrMsg: "sample 2 has no ---OUTPUT---",
+		},
+		{
+			name:           "failure-output_twice",
+			input:          "---INPUT---\n1\n---OUTPUT---\n1\n---OUTPUT---\n1\n",
+			expectedErrMsg: "line 5: ---OUTPUT--- must follow ---INPUT---",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			samples, err := parseStdinSamples(strings.NewReader(test.input))
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if len(samples) != len(test.expectedSamples) {
+				t.Fatalf("length of samples wrong. want=%d, got=%d", len(test.expectedSamples), len(samples))
+			}
+			for i, expected := range test.expectedSamples {
+				if samples[i] != expected {
+					t.Fatalf("%d-th sample wrong. want=%+v, got=%+v", i, expected, samples[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApp_Run_stdinSamples(t *testing.T) {
+	setupHome(t)
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-stdin-samples", "-command", "cat"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	a.inStream = strings.NewReader("---INPUT---\n1\n---OUTPUT---\n1\n---INPUT---\n2\n---OUTPUT---\n3\n")
+
+	if err := a.Run(); err != ErrSamplesFailed {
+		t.Fatalf("err wrong. want=%v, got=%v", ErrSamplesFailed, err)
+	}
+	for _, expected := range []string{"sample 1: SUCCESS", "sample 2: FAILURE"} {
+		if !strings.Contains(outStream.String(), expected) {
+			t.Errorf("expect '%s' to contain '%s'", outStream.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	"io"
+
+	"github.com/mui87/atctest/atcoder"
+)
+
+// ansiStripWriter writes to the file without ANSI escape sequences, which are only meaningful on the terminal.
+type ansiStripWriter struct {
+	w io.Writer
+}
+
+func (a *ansiStripWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(a.w, atcoder.StripANSI(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
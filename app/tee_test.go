@@ -0,0 +1,54 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestApp_Run_tee(t *testing.T) {
+	home := setupHome(t)
+	teePath := path.Join(home, "practice.log")
+
+	var outStream, errStream bytes.Buffer
+	a, err := New([]string{"atctest", "-tee", teePath, "-input", "1", "-expected", "1", "-command", "cat"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	bytes, err := ioutil.ReadFile(teePath)
+	if err != nil {
+		t.Fatalf("failed to read the file of -tee: %s", err)
+	}
+	if string(bytes) != outStream.String() {
+		t.Fatalf("file of -tee wrong.\nwant:\n%s\ngot:\n%s", outStream.String(), string(bytes))
+	}
+	if !strings.Contains(string(bytes), "sample 1: SUCCESS") {
+		t.Fatalf("expect '%s' to contain '%s'", string(bytes), "sample 1: SUCCESS")
+	}
+}
+
+func TestAnsiStripWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &ansiStripWriter{w: &buf}
+
+	input := "sample 1: \x1b[32mSUCCESS\x1b[0m\n"
+	n, err := w.Write([]byte(input))
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if n != len(input) {
+		t.Fatalf("written length wrong. want=%d, got=%d", len(input), n)
+	}
+	if buf.String() != "sample 1: SUCCESS\n" {
+		t.Fatalf("written string wrong. got: %q", buf.String())
+	}
+}
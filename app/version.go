@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// Version, Commit and Date are set when atctest is built, e.g.)
+// go build -ldflags "-X github.com/mui87/atctest/app.Version=v1.2.0 -X github.com/mui87/atctest/app.Commit=$(git rev-parse --short HEAD) -X github.com/mui87/atctest/app.Date=$(date -u +%Y-%m-%d)"
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// versionString returns the version of atctest, with the commit and the build date if known.
+// the ones not given by -ldflags are taken from the build info of the go command, e.g.) for go install.
+func versionString() string {
+	commit, date := Commit, Date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && commit == "":
+				commit = setting.Value
+				if len(commit) > 7 {
+					commit = commit[:7]
+				}
+			case setting.Key == "vcs.time" && date == "":
+				date = setting.Value
+			}
+		}
+	}
+
+	var details []string
+	if commit != "" {
+		details = append(details, "commit "+commit)
+	}
+	if date != "" {
+		details = append(details, "built "+date)
+	}
+	if len(details) == 0 {
+		return "atctest " + Version
+	}
+	return fmt.Sprintf("atctest %s (%s)", Version, strings.Join(details, ", "))
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestApp_Run_version(t *testing.T) {
+	defer func(version, commit, date string) {
+		Version, Commit, Date = version, commit, date
+	}(Version, Commit, Date)
+	Version, Commit, Date = "v1.2.0", "abc1234", "2019-05-01"
+
+	var outStream, errStream bytes.Buffer
+	// no contest, problem nor command is needed
+	a, err := New([]string{"atctest", "-version"}, &outStream, &errStream)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := a.Run(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	expected := "atctest v1.2.0 (commit abc1234, built 2019-05-01)\n"
+	if outStream.String() != expected {
+		t.Fatalf("output wrong. want='%s', got='%s'", expected, outStream.String())
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	defer func(version, commit, date string) {
+		Version, Commit, Date = version, commit, date
+	}(Version, Commit, Date)
+	Version, Commit, Date = "v1.2.0", "", ""
+
+	if got := versionString(); !strings.HasPrefix(got, "atctest v1.2.0") {
+		t.Fatalf("expect '%s' to start with '%s'", got, "atctest v1.2.0")
+	}
+}
@@ -0,0 +1,159 @@
+package atcoder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// cacheEntry is the content of a cache file. the URL is kept for readability since the file name is a hash of it.
+type cacheEntry struct {
+	URL     string   `json:"url"`
+	Samples []Sample `json:"samples"`
+	// Problems is set instead of Samples for the problem list page of a contest.
+	Problems []Problem `json:"problems,omitempty"`
+	// FetchedAt is when the page was fetched. it is zero for the files written by older versions.
+	FetchedAt time.Time `json:"fetched_at,omitempty"`
+}
+
+// SetCacheTTL makes the cache files older than the duration ignored, to take the samples fixed on AtCoder.
+// the files without the time they were fetched are ignored too. 0, the default, means they never expire.
+func (c *Client) SetCacheTTL(ttl time.Duration) {
+	c.cacheTTL = ttl
+}
+
+// expired reports whether the cache entry is older than the TTL.
+func (c *Client) expired(entry cacheEntry) bool {
+	return c.cacheTTL > 0 && (entry.FetchedAt.IsZero() || time.Since(entry.FetchedAt) > c.cacheTTL)
+}
+
+// cacheFilePath returns the path of the cache file named after the hash of the URL,
+// which avoids too long file names for deep URLs.
+func (c *Client) cacheFilePath(problemURL string) string {
+	sum := sha256.Sum256([]byte(problemURL))
+	filename := fmt.Sprintf("%s.json", hex.EncodeToString(sum[:16]))
+	return path.Join(c.cacheDirPath, filename)
+}
+
+// legacyCacheFilePath returns the path of the cache file named after the escaped URL, used by older versions.
+func (c *Client) legacyCacheFilePath(problemURL string) string {
+	escapedURL := strings.Replace(problemURL, "/", "_", -1)
+	filename := fmt.Sprintf("%s.json", escapedURL)
+	return path.Join(c.cacheDirPath, filename)
+}
+
+func (c *Client) getCachedSamples(problemURL string) ([]Sample, bool) {
+	_, err := os.Stat(c.cacheDirPath)
+	if err != nil {
+		return nil, false
+	}
+
+	filePath := c.cacheFilePath(problemURL)
+	bytes, err := ioutil.ReadFile(filePath)
+	if err == nil {
+		var entry cacheEntry
+		if err := json.Unmarshal(bytes, &entry); err != nil {
+			c.removeCorruptCache(filePath, err)
+			return nil, false
+		}
+		if entry.URL != problemURL || c.expired(entry) {
+			return nil, false
+		}
+		return entry.Samples, true
+	}
+
+	return c.getLegacyCachedSamples(problemURL)
+}
+
+// getLegacyCachedSamples reads the cache file of older versions and migrates it to the current scheme.
+func (c *Client) getLegacyCachedSamples(problemURL string) ([]Sample, bool) {
+	if c.cacheTTL > 0 {
+		// it is not known when they were fetched
+		return nil, false
+	}
+	legacyPath := c.legacyCacheFilePath(problemURL)
+	bytes, err := ioutil.ReadFile(legacyPath)
+	if err != nil {
+		return nil, false
+	}
+
+	var samples []Sample
+	if err := json.Unmarshal(bytes, &samples); err != nil {
+		return nil, false
+	}
+
+	if c.readOnlyCache {
+		return samples, true
+	}
+	// the time it was fetched is left unknown
+	if err := c.writeCacheEntry(cacheEntry{URL: problemURL, Samples: samples}); err == nil {
+		_ = os.Remove(legacyPath)
+	}
+
+	return samples, true
+}
+
+func (c *Client) cacheSamples(problemURL string, samples []Sample) error {
+	return c.writeCacheEntry(cacheEntry{URL: problemURL, Samples: samples, FetchedAt: time.Now()})
+}
+
+func (c *Client) getCachedProblems(problemListURL string) ([]Problem, bool) {
+	filePath := c.cacheFilePath(problemListURL)
+	bytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(bytes, &entry); err != nil {
+		c.removeCorruptCache(filePath, err)
+		return nil, false
+	}
+	if entry.URL != problemListURL || len(entry.Problems) == 0 || c.expired(entry) {
+		return nil, false
+	}
+	return entry.Problems, true
+}
+
+func (c *Client) cacheProblems(problemListURL string, problems []Problem) error {
+	return c.writeCacheEntry(cacheEntry{URL: problemListURL, Problems: problems, FetchedAt: time.Now()})
+}
+
+// removeCorruptCache removes the cache file which cannot be parsed, e.g.) truncated or edited by hand.
+// the page is fetched again and cached, unless the cache is read-only, in which case the file is left as it is.
+func (c *Client) removeCorruptCache(filePath string, err error) {
+	if c.readOnlyCache {
+		_, _ = fmt.Fprintf(c.errStream, "[WARNING] the cache file %s is corrupted: %s\n", filePath, err)
+		return
+	}
+	_, _ = fmt.Fprintf(c.errStream, "[WARNING] the cache file %s is corrupted and removed: %s\n", filePath, err)
+	_ = os.Remove(filePath)
+}
+
+func (c *Client) writeCacheEntry(entry cacheEntry) error {
+	if c.readOnlyCache {
+		return nil
+	}
+
+	_, err := os.Stat(c.cacheDirPath)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(c.cacheDirPath, 0777); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.cacheFilePath(entry.URL), bytes, 0644)
+}
@@ -0,0 +1,201 @@
+package atcoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestClient_getCachedSamples(t *testing.T) {
+	const problemURL = dummyBaseURL + "/contests/abc124/tasks/abc124_b"
+	samples := []Sample{
+		{Input: "4\n6 5 6 8\n", Output: "3\n"},
+	}
+
+	t.Run("current scheme", func(t *testing.T) {
+		defer os.RemoveAll(dummyCacheDirPath)
+
+		c := &Client{cacheDirPath: dummyCacheDirPath}
+		if err := c.cacheSamples(problemURL, samples); err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+
+		filename := path.Base(c.cacheFilePath(problemURL))
+		if strings.Contains(filename, "abc124") {
+			t.Fatalf("cache file name should not contain the URL. got: %s", filename)
+		}
+
+		actual, ok := c.getCachedSamples(problemURL)
+		if !ok {
+			t.Fatal("cache should be hit")
+		}
+		if len(actual) != 1 || actual[0] != samples[0] {
+			t.Fatalf("cached samples wrong. want=%+v, got=%+v", samples, actual)
+		}
+	})
+
+	t.Run("legacy scheme", func(t *testing.T) {
+		defer os.RemoveAll(dummyCacheDirPath)
+
+		c := &Client{cacheDirPath: dummyCacheDirPath}
+		if err := os.MkdirAll(dummyCacheDirPath, 0777); err != nil {
+			t.Fatalf("failed to create dummy cache dir: %s", err)
+		}
+		b, err := json.Marshal(samples)
+		if err != nil {
+			t.Fatalf("failed to marshal samples: %s", err)
+		}
+		legacyPath := c.legacyCacheFilePath(problemURL)
+		if err := ioutil.WriteFile(legacyPath, b, 0644); err != nil {
+			t.Fatalf("failed to create cache file: %s", err)
+		}
+
+		actual, ok := c.getCachedSamples(problemURL)
+		if !ok {
+			t.Fatal("cache should be hit")
+		}
+		if len(actual) != 1 || actual[0] != samples[0] {
+			t.Fatalf("cached samples wrong. want=%+v, got=%+v", samples, actual)
+		}
+
+		if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+			t.Fatal("legacy cache file should be removed after migration")
+		}
+		if _, err := os.Stat(c.cacheFilePath(problemURL)); err != nil {
+			t.Fatalf("cache file should be migrated. got: %s", err)
+		}
+	})
+	t.Run("read-only", func(t *testing.T) {
+		defer os.RemoveAll(dummyCacheDirPath)
+
+		c := &Client{cacheDirPath: dummyCacheDirPath}
+		if err := c.cacheSamples(problemURL, samples); err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+
+		c.SetReadOnlyCache(true)
+		if _, ok := c.getCachedSamples(problemURL); !ok {
+			t.Fatal("cache should be hit")
+		}
+
+		const otherURL = dummyBaseURL + "/contests/abc124/tasks/abc124_c"
+		if err := c.cacheSamples(otherURL, samples); err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+		if _, err := os.Stat(c.cacheFilePath(otherURL)); !os.IsNotExist(err) {
+			t.Fatal("cache file should not be written")
+		}
+	})
+}
+
+func TestClient_SetCacheTTL(t *testing.T) {
+	const problemURL = dummyBaseURL + "/contests/abc124/tasks/abc124_b"
+	samples := []Sample{
+		{Input: "4\n6 5 6 8\n", Output: "3\n"},
+	}
+
+	tests := []struct {
+		name          string
+		inputTTL      time.Duration
+		mockFetchedAt time.Time
+
+		expectedHit bool
+	}{
+		{
+			name:          "no ttl",
+			mockFetchedAt: time.Now().Add(-24 * 365 * time.Hour),
+			expectedHit:   true,
+		},
+		{
+			name:          "within ttl",
+			inputTTL:      24 * time.Hour,
+			mockFetchedAt: time.Now().Add(-time.Hour),
+			expectedHit:   true,
+		},
+		{
+			name:          "expired",
+			inputTTL:      24 * time.Hour,
+			mockFetchedAt: time.Now().Add(-25 * time.Hour),
+			expectedHit:   false,
+		},
+		{
+			name:        "unknown fetch time",
+			inputTTL:    24 * time.Hour,
+			expectedHit: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer os.RemoveAll(dummyCacheDirPath)
+
+			c := &Client{cacheDirPath: dummyCacheDirPath}
+			if err := c.writeCacheEntry(cacheEntry{URL: problemURL, Samples: samples, FetchedAt: test.mockFetchedAt}); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			c.SetCacheTTL(test.inputTTL)
+			if _, ok := c.getCachedSamples(problemURL); ok != test.expectedHit {
+				t.Fatalf("cache hit wrong. want=%t, got=%t", test.expectedHit, ok)
+			}
+		})
+	}
+}
+
+func TestClient_GetSamples_corruptCache(t *testing.T) {
+	const problemURL = dummyBaseURL + "/contests/abc124/tasks/abc124_b"
+	defer os.RemoveAll(dummyCacheDirPath)
+
+	html, err := ioutil.ReadFile(path.Join("testdata", "problem", "abc124b.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gock.Off()
+	gock.New(dummyBaseURL).
+		Get("/contests/abc124/tasks/abc124_b").
+		Reply(http.StatusOK).
+		AddHeader("Content-Type", "text/html").
+		BodyString(string(html))
+
+	var errBuff bytes.Buffer
+	c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), useCache: true, cacheDirPath: dummyCacheDirPath, errStream: &errBuff}
+	if err := os.MkdirAll(dummyCacheDirPath, 0777); err != nil {
+		t.Fatalf("failed to create dummy cache dir: %s", err)
+	}
+	// e.g.) truncated by a full disk
+	if err := ioutil.WriteFile(c.cacheFilePath(problemURL), []byte(`{"url": "`+problemURL+`", "samples": [{"in`), 0644); err != nil {
+		t.Fatalf("failed to create cache file: %s", err)
+	}
+
+	samples, err := c.GetSamples(problemURL)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if len(samples) == 0 {
+		t.Fatal("samples should be fetched again")
+	}
+	if !strings.Contains(errBuff.String(), "is corrupted and removed") {
+		t.Fatalf("expect '%s' to contain '%s'", errBuff.String(), "is corrupted and removed")
+	}
+
+	// the cache is recovered with the fetched samples
+	errBuff.Reset()
+	cached, ok := c.getCachedSamples(problemURL)
+	if !ok {
+		t.Fatal("cache should be hit")
+	}
+	if len(cached) != len(samples) {
+		t.Fatalf("length of cached samples wrong. want=%d, got=%d", len(samples), len(cached))
+	}
+	if errBuff.String() != "" {
+		t.Fatalf("errStream should be empty. got: %s", errBuff.String())
+	}
+}
@@ -1,61 +1,500 @@
 package atcoder
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/mui87/atctest/commander"
 )
 
+const defaultDiffContext = 3
+
+var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+type Options struct {
+	// Compare is how the outputs are compared. the options for the text like Round are ignored with CompareJSON.
+	Compare CompareMode
+	// StripANSI removes ANSI escape sequences from the output of the program before comparison.
+	StripANSI bool
+	// IgnoreLines removes the lines of the output of the program matching it before comparison, e.g.) debug prints. nil keeps all.
+	IgnoreLines *regexp.Regexp
+	// NumericInteger compares integer tokens by their values, ignoring leading zeros and plus signs.
+	NumericInteger bool
+	// Round rounds numeric tokens to RoundDigits digits after the decimal point before comparison.
+	Round       bool
+	RoundDigits int
+	// Tolerance accepts numeric tokens whose absolute or relative error is within it. 0 disables it.
+	Tolerance float64
+	// SortLineTokens sorts the whitespace-separated tokens of each line before comparison,
+	// for the problems which accept the tokens in a line in any order.
+	SortLineTokens bool
+	// CommandPrefix is prepended to the command as is, e.g.) 'nice -n 10'. it is not prepended to InputTransform.
+	CommandPrefix string
+	// InputTransform is a command which the input of each sample is piped through before it is given to the program.
+	InputTransform string
+	// InputMode is how the input of each sample is given to the program. it is given after InputTransform.
+	InputMode InputMode
+	// Checker is the command which decides whether the output is accepted instead of comparing it with the expected one,
+	// for the problems with multiple valid outputs. the options of the comparison like Compare are ignored with it. see judge.
+	Checker string
+	// WarnSlow is the duration over which a passing sample is marked as slow. 0 disables it.
+	WarnSlow time.Duration
+	// ShowDiff shows the difference between the expected and actual output on FAILURE instead of both of them.
+	ShowDiff bool
+	// DiffMaxLines is the number of differing lines shown by ShowDiff before the rest is omitted. 0 means no limit.
+	DiffMaxLines int
+	// HexDump also shows the bytes around the first difference between the expected and actual output on FAILURE.
+	HexDump bool
+	// Strict compares the outputs byte by byte, instead of ignoring CRLF line endings,
+	// trailing whitespace of each line and trailing blank lines as the judge of AtCoder does.
+	Strict bool
+	// TrailingNewline controls whether the output must, must not, or may end with a newline.
+	TrailingNewline TrailingNewline
+	// DelayBetweenSamples is the duration to wait before running each sample except the first one.
+	DelayBetweenSamples time.Duration
+	// ExitCode is the exit code the program is expected to exit with. the output is compared only when it exits with it.
+	ExitCode int
+	// RuntimeError reports the run exited with another code than ExitCode as StatusRuntimeError instead of StatusError.
+	RuntimeError bool
+	// MemoryLimit is the peak memory usage in bytes over which the run is reported as StatusMemoryLimit. 0 means no limit.
+	// it is ignored where the memory usage is not measured. see commander.MemoryMeasured.
+	MemoryLimit int64
+	// Timeout is the duration after which the run of a sample is killed. 0 means no limit.
+	Timeout time.Duration
+	// Deadline is the time after which the runs of all the samples are killed. the zero time means no limit.
+	Deadline time.Time
+	// FailFast stops running the samples after the first one which does not pass.
+	// the results of the samples not run are left out.
+	FailFast bool
+	// Jobs is the number of samples run concurrently. the results are still reported in the order of the samples.
+	// 0 and 1 mean the samples are run one by one.
+	Jobs int
+}
+
 type Checker struct {
 	commander commander.Commander
+	opts      Options
+	progress  func(Event)
+
+	mu        sync.Mutex
 	outStream io.Writer
 	errStream io.Writer
 }
 
-func NewChecker(outStream, errStream io.Writer) *Checker {
+func NewChecker(opts Options, outStream, errStream io.Writer) *Checker {
 	return &Checker{
 		commander: commander.NewExternal(),
+		opts:      opts,
 		outStream: outStream,
 		errStream: errStream,
 	}
 }
 
+// SetCommander replaces the commander which runs the commands, e.g.) with commander.PTY.
+func (c *Checker) SetCommander(cmd commander.Commander) {
+	c.commander = cmd
+}
+
+// SetProgress sets the function called with the progress of checking samples.
+// the calls never overlap, so the function needs no locking of its own.
+func (c *Checker) SetProgress(progress func(Event)) {
+	c.progress = progress
+}
+
+// Check runs the command for the samples like CheckAndRender, and reports whether all of them passed.
 func (c *Checker) Check(command string, samples []Sample) bool {
 	successAll := true
+	for _, result := range c.CheckAndRender(command, samples) {
+		if !result.Success() {
+			successAll = false
+		}
+	}
+
+	return successAll
+}
+
+// CheckAndRender runs the command for the samples, writing the result of each sample as soon as it is available.
+func (c *Checker) CheckAndRender(command string, samples []Sample) []Result {
+	results := checkSamples(c.commander, command, samples, c.opts, func(e Event) {
+		if e.Type == EventFinished {
+			c.render(*e.Result)
+		}
+		c.notify(e)
+	})
+	if len(results) < len(samples) {
+		_, _ = fmt.Fprintf(c.outStream, "stopping after the first failure. %d of %d samples were not run.\n", len(samples)-len(results), len(samples))
+	}
+	return results
+}
+
+// CheckResults runs the command for the samples like Check, but writes nothing and returns the results.
+func (c *Checker) CheckResults(command string, samples []Sample) []Result {
+	return checkSamples(c.commander, command, samples, c.opts, c.notify)
+}
+
+func (c *Checker) notify(e Event) {
+	if c.progress == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress(e)
+}
+
+// CheckSamples runs the command with the input of each sample and compares its output with the expected output.
+// it writes nothing, so that callers can render the results by themselves.
+func CheckSamples(cmd commander.Commander, command string, samples []Sample, opts Options) []Result {
+	return checkSamples(cmd, command, samples, opts, nil)
+}
+
+// CheckSamplesWithProgress runs the samples like CheckSamples, calling progress when each sample starts and finishes.
+func CheckSamplesWithProgress(cmd commander.Commander, command string, samples []Sample, opts Options, progress func(Event)) []Result {
+	return checkSamples(cmd, command, samples, opts, progress)
+}
+
+// checkSamples calls progress, if given, when each sample starts and as soon as its result is available.
+func checkSamples(cmd commander.Commander, command string, samples []Sample, opts Options, progress func(Event)) []Result {
+	if opts.Jobs > 1 {
+		return checkSamplesConcurrently(cmd, command, samples, opts, progress)
+	}
+
+	results := make([]Result, 0, len(samples))
 	for i, sample := range samples {
-		success, actual, err := c.checkOne(command, sample)
-		_, _ = fmt.Fprintf(c.outStream, "sample %d: ", i+1)
+		if i > 0 && opts.DelayBetweenSamples > 0 {
+			time.Sleep(opts.DelayBetweenSamples)
+		}
+
+		if progress != nil {
+			progress(Event{Type: EventStarted, Index: i, Sample: sample})
+		}
+		result := checkOne(cmd, command, sample, opts)
+		result.Index = i
+		if progress != nil {
+			progress(Event{Type: EventFinished, Index: i, Sample: sample, Result: &result})
+		}
+		results = append(results, result)
+		if opts.FailFast && !result.Success() {
+			break
+		}
+	}
+
+	return results
+}
+
+// checkSamplesConcurrently runs up to opts.Jobs samples at once.
+// the results finished ahead are held until all the preceding ones are finished, so that EventFinished is emitted in order.
+// the calls of progress never overlap.
+func checkSamplesConcurrently(cmd commander.Commander, command string, samples []Sample, opts Options, progress func(Event)) []Result {
+	results := make([]Result, len(samples))
+	finished := make([]bool, len(samples))
+	next := 0
+	// limit is the number of results reported, which is less than the samples after a failure with opts.FailFast
+	limit := len(samples)
+
+	var mu sync.Mutex
+	emit := func(e Event) {
+		if progress != nil {
+			progress(e)
+		}
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				mu.Lock()
+				emit(Event{Type: EventStarted, Index: i, Sample: samples[i]})
+				mu.Unlock()
+
+				result := checkOne(cmd, command, samples[i], opts)
+				result.Index = i
+
+				mu.Lock()
+				results[i] = result
+				finished[i] = true
+				for ; next < limit && finished[next]; next++ {
+					emit(Event{Type: EventFinished, Index: next, Sample: samples[next], Result: &results[next]})
+					if opts.FailFast && !results[next].Success() {
+						limit = next + 1
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range samples {
+		if i > 0 && opts.DelayBetweenSamples > 0 {
+			time.Sleep(opts.DelayBetweenSamples)
+		}
+		mu.Lock()
+		stopped := i >= limit
+		mu.Unlock()
+		if stopped {
+			break
+		}
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results[:limit]
+}
+
+// render writes the result of a sample to outStream at once so that results of samples never interleave.
+func (c *Checker) render(result Result) {
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "sample %d: ", result.Number())
+	switch result.Status {
+	case StatusError:
+		_, _ = color.New(color.FgRed).Fprint(&buf, result.Status)
+		_, _ = fmt.Fprintf(&buf, " (%.2fs)\n", result.Elapsed.Seconds())
+		_, _ = fmt.Fprintln(&buf, result.Err.Error())
+		writeStderr(&buf, result.Stderr)
+		writeCheckerMessage(&buf, result.CheckerMessage)
+	case StatusRuntimeError:
+		_, _ = color.New(color.FgRed).Fprint(&buf, result.Status)
+		_, _ = fmt.Fprintf(&buf, " (exit code %d, %.2fs)\n", result.ExitCode, result.Elapsed.Seconds())
+		_, _ = fmt.Fprintln(&buf, result.Err.Error())
+		writeStderr(&buf, result.Stderr)
+	case StatusTimeout:
+		_, _ = color.New(color.FgYellow).Fprint(&buf, result.Status)
+		_, _ = fmt.Fprintf(&buf, " (killed after %.2fs)\n", result.Elapsed.Seconds())
+	case StatusMemoryLimit:
+		_, _ = color.New(color.FgYellow).Fprint(&buf, result.Status)
+		_, _ = fmt.Fprintf(&buf, " (%.2fs, %s > %s)\n", result.Elapsed.Seconds(), formatMemory(result.MaxRSS), formatMemory(c.opts.MemoryLimit))
+	case StatusSuccess:
+		if c.opts.WarnSlow > 0 && result.Elapsed > c.opts.WarnSlow {
+			_, _ = color.New(color.FgGreen).Fprint(&buf, result.Status)
+			_, _ = color.New(color.FgYellow).Fprintf(&buf, " (slow: %.2fs > %s%s)\n", result.Elapsed.Seconds(), c.opts.WarnSlow, memoryNote(result))
+			break
+		}
+		_, _ = color.New(color.FgGreen).Fprint(&buf, result.Status)
+		_, _ = fmt.Fprintf(&buf, " (%.2fs%s)\n", result.Elapsed.Seconds(), memoryNote(result))
+	default:
+		_, _ = color.New(color.FgRed).Fprint(&buf, result.Status)
+		_, _ = fmt.Fprintf(&buf, " (%.2fs%s)\n", result.Elapsed.Seconds(), memoryNote(result))
+		_, _ = fmt.Fprintln(&buf, "input:")
+		_, _ = fmt.Fprint(&buf, result.Sample.Input)
+		c.renderOutputs(&buf, result)
+		writeCheckerMessage(&buf, result.CheckerMessage)
+		if c.opts.HexDump {
+			_, _ = fmt.Fprint(&buf, HexDump(result.Sample.Output, result.Actual))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, _ = c.outStream.Write(buf.Bytes())
+}
+
+// renderOutputs writes the difference between the expected and actual output with ShowDiff, or both of them.
+func (c *Checker) renderOutputs(buf *bytes.Buffer, result Result) {
+	if c.opts.ShowDiff {
+		if diff := Diff(result.Sample.Output, result.Actual, DiffOptions{Context: defaultDiffContext, Color: true, MaxLines: c.opts.DiffMaxLines}); diff != "" {
+			_, _ = fmt.Fprintln(buf, "diff (-expected +actual):")
+			_, _ = fmt.Fprint(buf, diff)
+			return
+		}
+	}
+	_, _ = fmt.Fprintln(buf, "expected output:")
+	writeOutput(buf, result.Sample.Output)
+	_, _ = fmt.Fprintln(buf, "actual output:")
+	writeOutput(buf, result.Actual)
+}
+
+// memoryNote returns the peak memory usage to be shown after the elapsed time, or an empty string if it is not measured.
+func memoryNote(result Result) string {
+	if result.MaxRSS == 0 {
+		return ""
+	}
+	return ", " + formatMemory(result.MaxRSS)
+}
+
+// formatMemory formats the bytes in MB, the unit of the memory limits of AtCoder, e.g.) "12.3 MB".
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+}
+
+// writeStderr writes the stderr of the program if any, ending it with a newline.
+func writeStderr(buf *bytes.Buffer, stderr string) {
+	if stderr == "" {
+		return
+	}
+	_, _ = fmt.Fprintln(buf, "stderr:")
+	_, _ = fmt.Fprint(buf, stderr)
+	if !strings.HasSuffix(stderr, "\n") {
+		_, _ = fmt.Fprintln(buf)
+	}
+}
+
+// writeCheckerMessage writes the message of the checker if any, ending it with a newline.
+func writeCheckerMessage(buf *bytes.Buffer, message string) {
+	if message == "" {
+		return
+	}
+	_, _ = fmt.Fprintln(buf, "checker message:")
+	_, _ = fmt.Fprint(buf, message)
+	if !strings.HasSuffix(message, "\n") {
+		_, _ = fmt.Fprintln(buf)
+	}
+}
+
+// writeOutput writes the output, marking the empty one so that it is not mistaken for a blank line.
+func writeOutput(buf *bytes.Buffer, output string) {
+	if output == "" {
+		_, _ = fmt.Fprintln(buf, "(empty)")
+		return
+	}
+	_, _ = fmt.Fprint(buf, output)
+}
+
+func checkOne(cmd commander.Commander, command string, sample Sample, opts Options) Result {
+	// the timeout covers InputTransform too, so that a transform which hangs is killed as well
+	ctx := context.Background()
+	if !opts.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, opts.Deadline)
+		defer cancel()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	input := sample.Input
+	if opts.InputTransform != "" {
+		start := time.Now()
+		transformed, err := cmd.Run(ctx, opts.InputTransform, input)
+		if ctx.Err() == context.DeadlineExceeded {
+			return Result{Sample: sample, Status: StatusTimeout, Elapsed: time.Since(start)}
+		}
 		if err != nil {
-			successAll = false
+			return Result{Sample: sample, Status: StatusError, Err: fmt.Errorf("failed to transform input: %s", err), Stderr: stderrOf(err)}
+		}
+		input = transformed
+	}
+
+	if opts.CommandPrefix != "" {
+		command = opts.CommandPrefix + " " + command
+	}
+
+	if opts.InputMode == InputModeFile {
+		path, remove, err := writeTempFile("input", input)
+		if err != nil {
+			return Result{Sample: sample, Status: StatusError, Err: err}
+		}
+		defer remove()
+		command = strings.Replace(command, InputPlaceholder, commander.Quote(path), -1)
+		input = ""
+	}
 
-			_, _ = color.New(color.FgRed).Fprintln(c.outStream, "ERROR")
-			_, _ = fmt.Fprintln(c.outStream, err.Error())
-		} else if success {
-			_, _ = color.New(color.FgGreen).Fprintln(c.outStream, "SUCCESS")
+	start := time.Now()
+	actualOutput, usage, err := commander.RunWithUsage(ctx, cmd, command, input)
+	elapsed := time.Since(start)
+	if ctx.Err() == context.DeadlineExceeded {
+		return Result{Sample: sample, Status: StatusTimeout, Elapsed: elapsed}
+	}
+	exitCode := 0
+	// unexpectedExit is whether the program exited by itself with another code than expected
+	unexpectedExit := false
+	var exitErr *commander.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.Code
+		if exitCode == opts.ExitCode {
+			err = nil
 		} else {
-			successAll = false
+			unexpectedExit = true
+		}
+	} else if err == nil && opts.ExitCode != 0 {
+		err = fmt.Errorf("exit status 0, expected %d", opts.ExitCode)
+		unexpectedExit = true
+	}
+	if err != nil {
+		if unexpectedExit && opts.RuntimeError {
+			return Result{Sample: sample, Status: StatusRuntimeError, Actual: actualOutput, Err: err, ExitCode: exitCode, Stderr: stderrOf(err), MaxRSS: usage.MaxRSS, Elapsed: elapsed}
+		}
+		return Result{Sample: sample, Status: StatusError, Err: err, ExitCode: exitCode, Stderr: stderrOf(err), MaxRSS: usage.MaxRSS, Elapsed: elapsed}
+	}
+	if opts.StripANSI {
+		actualOutput = StripANSI(actualOutput)
+	}
+	if opts.IgnoreLines != nil {
+		actualOutput = removeLines(actualOutput, opts.IgnoreLines)
+	}
 
-			_, _ = color.New(color.FgRed).Fprintln(c.outStream, "FAILURE")
-			_, _ = fmt.Fprintln(c.outStream, "input:")
-			_, _ = fmt.Fprint(c.outStream, sample.Input)
-			_, _ = fmt.Fprintln(c.outStream, "expected output:")
-			_, _ = fmt.Fprint(c.outStream, sample.Output)
-			_, _ = fmt.Fprintln(c.outStream, "actual output:")
-			_, _ = fmt.Fprint(c.outStream, actual)
+	result := Result{Sample: sample, Status: StatusFailure, Actual: actualOutput, ExitCode: exitCode, MaxRSS: usage.MaxRSS, Elapsed: elapsed}
+	if opts.Checker != "" {
+		accepted, message, err := judge(ctx, opts.Checker, sample, actualOutput)
+		if ctx.Err() == context.DeadlineExceeded {
+			return Result{Sample: sample, Status: StatusTimeout, Elapsed: time.Since(start)}
 		}
+		result.CheckerMessage = message
+		if err != nil {
+			result.Status = StatusError
+			result.Err = err
+			return result
+		}
+		if accepted {
+			result.Status = StatusSuccess
+		}
+	} else if opts.Compare == CompareJSON {
+		matched, err := matchJSON(sample.Output, actualOutput)
+		if err != nil {
+			result.Status = StatusError
+			result.Err = err
+			return result
+		}
+		if matched {
+			result.Status = StatusSuccess
+		}
+	} else if match(sample.Output, actualOutput, opts) {
+		result.Status = StatusSuccess
 	}
 
-	return successAll
+	if opts.MemoryLimit > 0 && result.MaxRSS > opts.MemoryLimit {
+		result.Status = StatusMemoryLimit
+	}
+	return result
 }
 
-func (c *Checker) checkOne(command string, sample Sample) (bool, string, error) {
-	actualOutput, err := c.commander.Run(command, sample.Input)
-	if err != nil {
-		return false, "", err
+// stderrOf returns the stderr of the program which exited with a non-zero code.
+func stderrOf(err error) string {
+	var exitErr *commander.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Stderr
 	}
-	success := actualOutput == sample.Output
+	return ""
+}
+
+// removeLines removes the lines matching the pattern, which is matched without the line ending.
+func removeLines(s string, pattern *regexp.Regexp) string {
+	var kept []string
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if line == "" {
+			continue
+		}
+		if pattern.MatchString(strings.TrimRight(line, "\r\n")) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "")
+}
 
-	return success, actualOutput, nil
+// StripANSI removes ANSI escape sequences like colors from the string.
+func StripANSI(s string) string {
+	return ansiEscapePattern.ReplaceAllString(s, "")
 }
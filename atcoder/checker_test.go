@@ -2,9 +2,19 @@ package atcoder
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/mui87/atctest/commander"
 )
 
 const dummyRawCommand = "hello"
@@ -12,11 +22,35 @@ const dummyRawCommand = "hello"
 func TestChecker_Check(t *testing.T) {
 	tests := []struct {
 		name            string
+		inputOptions    Options
 		inputSamples    []Sample
 		mockResults     []commandResult
 		expectedSuccess bool
 		expectedOutput  string
 	}{
+		{
+			name:         "failure-runtime error",
+			inputOptions: Options{RuntimeError: true},
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+			},
+			mockResults: []commandResult{
+				{output: "", err: &commander.ExitError{Code: 1, Stderr: "panic: index out of range"}},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "RUNTIME ERROR (exit code 1, 0.00s)\nexit status 1\nstderr:\npanic: index out of range\n",
+		},
+		{
+			name: "failure-error with stderr",
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+			},
+			mockResults: []commandResult{
+				{output: "", err: &commander.ExitError{Code: 1, Stderr: "Traceback (most recent call last):\n"}},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "ERROR (0.00s)\nexit status 1\nstderr:\nTraceback (most recent call last):\n",
+		},
 		{
 			name: "success",
 			inputSamples: []Sample{
@@ -30,6 +64,30 @@ func TestChecker_Check(t *testing.T) {
 			expectedSuccess: true,
 			expectedOutput:  "SUCCESS",
 		},
+		{
+			name: "failure-labeled with the numbers on the page",
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n", Number: 1},
+				{Input: "1 2\n", Output: "3\n", Number: 3},
+			},
+			mockResults: []commandResult{
+				{output: "1\n", err: nil},
+				{output: "99\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "sample 3: FAILURE",
+		},
+		{
+			name: "failure-empty expected",
+			inputSamples: []Sample{
+				{Input: "0\n", Output: ""},
+			},
+			mockResults: []commandResult{
+				{output: "0\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "expected output:\n(empty)\nactual output:\n0\n",
+		},
 		{
 			name: "failure-all failed",
 			inputSamples: []Sample{
@@ -41,7 +99,7 @@ func TestChecker_Check(t *testing.T) {
 				{output: "99\n", err: nil},
 			},
 			expectedSuccess: false,
-			expectedOutput:  "FAILURE\ninput:\n0 1",
+			expectedOutput:  "FAILURE (0.00s)\ninput:\n0 1",
 		},
 		{
 			name: "failure-some failed",
@@ -54,7 +112,7 @@ func TestChecker_Check(t *testing.T) {
 				{output: "99\n", err: nil},
 			},
 			expectedSuccess: false,
-			expectedOutput:  "FAILURE\ninput:\n1 2",
+			expectedOutput:  "FAILURE (0.00s)\ninput:\n1 2",
 		},
 		{
 			name: "failure-some error",
@@ -67,7 +125,171 @@ func TestChecker_Check(t *testing.T) {
 				{output: "", err: errors.New("some error")},
 			},
 			expectedSuccess: false,
-			expectedOutput:  "ERROR\nsome error",
+			expectedOutput:  "ERROR (0.00s)\nsome error",
+		},
+		{
+			name:         "failure-show_diff",
+			inputOptions: Options{ShowDiff: true},
+			inputSamples: []Sample{
+				{Input: "3\n", Output: "1\n2\n3\n"},
+			},
+			mockResults: []commandResult{
+				{output: "1\n2\n4\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "diff (-expected +actual):\n@@ -1,3 +1,3 @@\n 1\n 2\n-3\n+4\n",
+		},
+		{
+			name:         "failure-show_diff_max_lines",
+			inputOptions: Options{ShowDiff: true, DiffMaxLines: 1},
+			inputSamples: []Sample{
+				{Input: "3\n", Output: "1\n2\n3\n"},
+			},
+			mockResults: []commandResult{
+				{output: "1\n2\n4\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "diff (-expected +actual):\n@@ -1,3 +1,2 @@\n 1\n 2\n-3\n... 1 more differing lines are omitted\n",
+		},
+		{
+			name:         "failure-hexdump",
+			inputOptions: Options{HexDump: true},
+			inputSamples: []Sample{
+				{Input: "1\n", Output: "a b\n"},
+			},
+			mockResults: []commandResult{
+				{output: "a\u00a0b\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "00000000  61 20 62 0a             |a b.    |  61 c2 a0 62 0a          |a..b.   |\n",
+		},
+		{
+			name:         "failure-failfast",
+			inputOptions: Options{FailFast: true},
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+				{Input: "1 2\n", Output: "3\n"},
+				{Input: "2 3\n", Output: "5\n"},
+			},
+			mockResults: []commandResult{
+				{output: "1\n", err: nil},
+				{output: "", err: errors.New("some error")},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "sample 2: ERROR (0.00s)\nsome error\nstopping after the first failure. 1 of 3 samples were not run.\n",
+		},
+		{
+			name:         "success-failfast",
+			inputOptions: Options{FailFast: true},
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+				{Input: "1 2\n", Output: "3\n"},
+			},
+			mockResults: []commandResult{
+				{output: "1\n", err: nil},
+				{output: "3\n", err: nil},
+			},
+			expectedSuccess: true,
+			expectedOutput:  "sample 2: SUCCESS (0.00s)\n",
+		},
+		{
+			name:         "success-compare_json",
+			inputOptions: Options{Compare: CompareJSON},
+			inputSamples: []Sample{
+				{Input: "0\n", Output: "{\"a\": 1, \"b\": 2}\n"},
+			},
+			mockResults: []commandResult{
+				{output: "{\"b\":2,\"a\":1}\n", err: nil},
+			},
+			expectedSuccess: true,
+			expectedOutput:  "SUCCESS",
+		},
+		{
+			name:         "failure-compare_json_invalid",
+			inputOptions: Options{Compare: CompareJSON},
+			inputSamples: []Sample{
+				{Input: "0\n", Output: "{\"a\": 1}\n"},
+			},
+			mockResults: []commandResult{
+				{output: "a=1\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "ERROR (0.00s)\nthe output of your program is not valid JSON: ",
+		},
+		{
+			name:         "success-warn_slow",
+			inputOptions: Options{WarnSlow: 10 * time.Millisecond},
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+				{Input: "1 2\n", Output: "3\n"},
+			},
+			mockResults: []commandResult{
+				{output: "1\n"},
+				{output: "3\n", delay: 30 * time.Millisecond},
+			},
+			expectedSuccess: true,
+			expectedOutput:  "sample 1: SUCCESS (0.00s)\nsample 2: SUCCESS (slow: ",
+		},
+		{
+			name:         "failure-timeout",
+			inputOptions: Options{Timeout: 10 * time.Millisecond},
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+				{Input: "1 2\n", Output: "3\n"},
+			},
+			mockResults: []commandResult{
+				{output: "1\n"},
+				{output: "3\n", delay: time.Minute},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "sample 2: TIMEOUT (killed after ",
+		},
+		{
+			name:         "success-strip_ansi",
+			inputOptions: Options{StripANSI: true},
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+			},
+			mockResults: []commandResult{
+				{output: "\x1b[31m1\x1b[0m\n", err: nil},
+			},
+			expectedSuccess: true,
+			expectedOutput:  "SUCCESS",
+		},
+		{
+			name:         "success-ignore_lines",
+			inputOptions: Options{IgnoreLines: regexp.MustCompile(`^DEBUG:`)},
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+			},
+			mockResults: []commandResult{
+				{output: "DEBUG: a=0\n1\nDEBUG: done", err: nil},
+			},
+			expectedSuccess: true,
+			expectedOutput:  "SUCCESS",
+		},
+		{
+			name:         "failure-ignore_lines_not_matched",
+			inputOptions: Options{IgnoreLines: regexp.MustCompile(`^DEBUG:`)},
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+			},
+			mockResults: []commandResult{
+				{output: "[DEBUG: a=0]\n1\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "FAILURE",
+		},
+		{
+			name: "failure-ansi_not_stripped",
+			inputSamples: []Sample{
+				{Input: "0 1\n", Output: "1\n"},
+			},
+			mockResults: []commandResult{
+				{output: "\x1b[31m1\x1b[0m\n", err: nil},
+			},
+			expectedSuccess: false,
+			expectedOutput:  "FAILURE",
 		},
 	}
 	for _, test := range tests {
@@ -75,6 +297,7 @@ func TestChecker_Check(t *testing.T) {
 			var outStream bytes.Buffer
 			c := &Checker{
 				commander: &testCommander{index: 0, results: test.mockResults},
+				opts:      test.inputOptions,
 				outStream: &outStream,
 			}
 
@@ -89,22 +312,522 @@ func TestChecker_Check(t *testing.T) {
 	}
 }
 
+func TestCheckSamples(t *testing.T) {
+	cmd := &testCommander{results: []commandResult{
+		{output: "1\n"},
+		{output: "99\n"},
+		{err: errors.New("some error")},
+	}}
+	samples := []Sample{
+		{Input: "0 1\n", Output: "1\n"},
+		{Input: "1 2\n", Output: "3\n"},
+		{Input: "2 3\n", Output: "5\n"},
+	}
+
+	results := CheckSamples(cmd, dummyRawCommand, samples, Options{})
+	if len(results) != len(samples) {
+		t.Fatalf("length of results wrong. want=%d, got=%d", len(samples), len(results))
+	}
+
+	expected := []Result{
+		{Index: 0, Sample: samples[0], Status: StatusSuccess, Actual: "1\n"},
+		{Index: 1, Sample: samples[1], Status: StatusFailure, Actual: "99\n"},
+		{Index: 2, Sample: samples[2], Status: StatusError},
+	}
+	for i, want := range expected {
+		got := results[i]
+		if got.Index != want.Index || got.Sample != want.Sample || got.Status != want.Status || got.Actual != want.Actual {
+			t.Fatalf("%d-th result wrong.\nwant:\n%+v\ngot:\n%+v", i, want, got)
+		}
+		if (got.Err != nil) != (want.Status == StatusError) {
+			t.Fatalf("%d-th result err wrong. got: %v", i, got.Err)
+		}
+	}
+}
+
+func TestCheckSamples_exitCode(t *testing.T) {
+	sample := Sample{Input: "1\n", Output: "1\n"}
+	tests := []struct {
+		name         string
+		inputOptions Options
+		mockResult   commandResult
+
+		expectedStatus   Status
+		expectedExitCode int
+	}{
+		{
+			name:             "non-zero exit",
+			mockResult:       commandResult{output: "1\n", err: &commander.ExitError{Code: 1}},
+			expectedStatus:   StatusError,
+			expectedExitCode: 1,
+		},
+		{
+			name:             "non-zero exit as runtime error",
+			inputOptions:     Options{RuntimeError: true},
+			mockResult:       commandResult{output: "1\n", err: &commander.ExitError{Code: 139}},
+			expectedStatus:   StatusRuntimeError,
+			expectedExitCode: 139,
+		},
+		{
+			name:             "expected exit code",
+			inputOptions:     Options{ExitCode: 3, RuntimeError: true},
+			mockResult:       commandResult{output: "1\n", err: &commander.ExitError{Code: 3}},
+			expectedStatus:   StatusSuccess,
+			expectedExitCode: 3,
+		},
+		{
+			name:             "expected exit code with wrong output",
+			inputOptions:     Options{ExitCode: 3},
+			mockResult:       commandResult{output: "2\n", err: &commander.ExitError{Code: 3}},
+			expectedStatus:   StatusFailure,
+			expectedExitCode: 3,
+		},
+		{
+			name:             "zero exit while another code is expected",
+			inputOptions:     Options{ExitCode: 3, RuntimeError: true},
+			mockResult:       commandResult{output: "1\n"},
+			expectedStatus:   StatusRuntimeError,
+			expectedExitCode: 0,
+		},
+		{
+			name:             "other errors are not runtime errors",
+			inputOptions:     Options{RuntimeError: true},
+			mockResult:       commandResult{err: errors.New("failed to open pseudo-terminal")},
+			expectedStatus:   StatusError,
+			expectedExitCode: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := &testCommander{results: []commandResult{test.mockResult}}
+			result := CheckSamples(cmd, dummyRawCommand, []Sample{sample}, test.inputOptions)[0]
+			if result.Status != test.expectedStatus {
+				t.Fatalf("status wrong. want=%s, got=%s", test.expectedStatus, result.Status)
+			}
+			if result.ExitCode != test.expectedExitCode {
+				t.Fatalf("exit code wrong. want=%d, got=%d", test.expectedExitCode, result.ExitCode)
+			}
+		})
+	}
+}
+
+// usageCommander is testCommander which reports the peak memory usage of each run.
+type usageCommander struct {
+	testCommander
+	maxRSS int64
+}
+
+func (u *usageCommander) RunWithUsage(ctx context.Context, command, stdin string) (string, commander.Usage, error) {
+	out, err := u.Run(ctx, command, stdin)
+	return out, commander.Usage{MaxRSS: u.maxRSS}, err
+}
+
+func TestChecker_Check_memoryLimit(t *testing.T) {
+	tests := []struct {
+		name         string
+		inputOptions Options
+		mockMaxRSS   int64
+
+		expectedSuccess bool
+		expectedOutput  string
+	}{
+		{
+			name:            "memory is reported",
+			mockMaxRSS:      12900000,
+			expectedSuccess: true,
+			expectedOutput:  "SUCCESS (0.00s, 12.3 MB)",
+		},
+		{
+			name:            "within limit",
+			inputOptions:    Options{MemoryLimit: 256 << 20},
+			mockMaxRSS:      12900000,
+			expectedSuccess: true,
+			expectedOutput:  "SUCCESS (0.00s, 12.3 MB)",
+		},
+		{
+			name:            "over limit",
+			inputOptions:    Options{MemoryLimit: 256 << 20},
+			mockMaxRSS:      300 << 20,
+			expectedSuccess: false,
+			expectedOutput:  "MEMORY LIMIT EXCEEDED (0.00s, 300.0 MB > 256.0 MB)",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var outStream bytes.Buffer
+			c := &Checker{
+				commander: &usageCommander{testCommander: testCommander{results: []commandResult{{output: "1\n"}}}, maxRSS: test.mockMaxRSS},
+				opts:      test.inputOptions,
+				outStream: &outStream,
+			}
+
+			if success := c.Check(dummyRawCommand, []Sample{{Input: "0 1\n", Output: "1\n"}}); success != test.expectedSuccess {
+				t.Fatalf("success wrong. want=%t, got=%t", test.expectedSuccess, success)
+			}
+			if !strings.Contains(outStream.String(), test.expectedOutput) {
+				t.Fatalf("expect '%s' to contain '%s'", outStream.String(), test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestCheckSamples_elapsed(t *testing.T) {
+	cmd := &testCommander{results: []commandResult{
+		{output: "1\n", delay: 50 * time.Millisecond},
+		{output: "3\n"},
+	}}
+	samples := []Sample{
+		{Input: "0 1\n", Output: "1\n"},
+		{Input: "1 2\n", Output: "3\n"},
+	}
+
+	results := CheckSamples(cmd, dummyRawCommand, samples, Options{})
+	if results[0].Elapsed < 50*time.Millisecond {
+		t.Fatalf("elapsed time of sample 1 should include the run of the command. got: %s", results[0].Elapsed)
+	}
+	if results[1].Elapsed >= 50*time.Millisecond {
+		t.Fatalf("elapsed time of sample 2 should not include the other samples. got: %s", results[1].Elapsed)
+	}
+}
+
+func TestCheckSamples_inputTransform(t *testing.T) {
+	cmd := &testCommander{results: []commandResult{
+		{output: "1 2\n"},
+		{output: "3\n"},
+		{err: errors.New("invalid input")},
+	}}
+	samples := []Sample{
+		{Input: "MSAy\n", Output: "3\n"},
+		{Input: "!!\n", Output: "3\n"},
+	}
+
+	results := CheckSamples(cmd, dummyRawCommand, samples, Options{InputTransform: "base64 -d"})
+	if results[0].Status != StatusSuccess {
+		t.Fatalf("status of 1st result wrong. want=%s, got=%s", StatusSuccess, results[0].Status)
+	}
+	if cmd.stdins[1] != "1 2\n" {
+		t.Fatalf("program should be given the transformed input. got: %q", cmd.stdins[1])
+	}
+	if results[1].Status != StatusError || !strings.Contains(results[1].Err.Error(), "failed to transform input") {
+		t.Fatalf("2nd result should be the error of transform. got: %+v", results[1])
+	}
+}
+
+func TestCheckSamples_inputTransformTimeout(t *testing.T) {
+	samples := []Sample{
+		{Input: "1 2\n", Output: "3\n"},
+	}
+
+	start := time.Now()
+	results := CheckSamples(commander.NewExternal(), "cat", samples, Options{InputTransform: "sleep 10", Timeout: 100 * time.Millisecond})
+	if results[0].Status != StatusTimeout {
+		t.Fatalf("status wrong. want=%s, got=%s: %+v", StatusTimeout, results[0].Status, results[0])
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("the transform should be killed on the timeout. took: %s", elapsed)
+	}
+}
+
+func TestCheckSamples_commandPrefix(t *testing.T) {
+	cmd := &testCommander{results: []commandResult{
+		{output: "1 2\n"},
+		{output: "3\n"},
+	}}
+	samples := []Sample{
+		{Input: "MSAy\n", Output: "3\n"},
+	}
+
+	CheckSamples(cmd, "python c.py", samples, Options{InputTransform: "base64 -d", CommandPrefix: "taskset -c 0"})
+	expected := []string{"base64 -d", "taskset -c 0 python c.py"}
+	for i, command := range expected {
+		if cmd.commands[i] != command {
+			t.Fatalf("%d-th command wrong. want=%q, got=%q", i, command, cmd.commands[i])
+		}
+	}
+}
+
+func TestCheckSamples_inputModeFile(t *testing.T) {
+	samples := []Sample{
+		{Input: "1 2\n", Output: "3\n"},
+	}
+
+	// the program fails if anything is given through stdin
+	command := `test -z "$(cat)" && awk '{ print $1 + $2 }' {input}`
+	results := CheckSamples(commander.NewExternal(), command, samples, Options{InputMode: InputModeFile})
+	if results[0].Status != StatusSuccess {
+		t.Fatalf("status wrong. want=%s, got=%s: %+v", StatusSuccess, results[0].Status, results[0])
+	}
+}
+
+func TestCheckSamples_inputModeFileQuoted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atctest-input")
+	if err != nil {
+		t.Fatalf("failed to create dummy dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	tmpDir := filepath.Join(dir, "it's a dir")
+	if err := os.Mkdir(tmpDir, 0777); err != nil {
+		t.Fatalf("failed to create dummy dir: %s", err)
+	}
+	t.Setenv("TMPDIR", tmpDir)
+
+	samples := []Sample{
+		{Input: "1 2\n", Output: "3\n"},
+	}
+	results := CheckSamples(commander.NewExternal(), `awk '{ print $1 + $2 }' {input}`, samples, Options{InputMode: InputModeFile})
+	if results[0].Status != StatusSuccess {
+		t.Fatalf("status wrong. want=%s, got=%s: %+v", StatusSuccess, results[0].Status, results[0])
+	}
+}
+
+func TestCheckSamplesWithProgress(t *testing.T) {
+	cmd := &testCommander{results: []commandResult{
+		{output: "1\n"},
+		{output: "99\n"},
+	}}
+	samples := []Sample{
+		{Input: "0 1\n", Output: "1\n"},
+		{Input: "1 2\n", Output: "3\n"},
+	}
+
+	var events []Event
+	CheckSamplesWithProgress(cmd, dummyRawCommand, samples, Options{}, func(e Event) {
+		events = append(events, e)
+	})
+
+	expected := []struct {
+		eventType EventType
+		index     int
+		status    Status
+	}{
+		{eventType: EventStarted, index: 0},
+		{eventType: EventFinished, index: 0, status: StatusSuccess},
+		{eventType: EventStarted, index: 1},
+		{eventType: EventFinished, index: 1, status: StatusFailure},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("length of events wrong. want=%d, got=%d", len(expected), len(events))
+	}
+	for i, e := range expected {
+		actual := events[i]
+		if actual.Type != e.eventType || actual.Index != e.index {
+			t.Fatalf("%d-th event wrong. want=%s of %d, got=%s of %d", i, e.eventType, e.index, actual.Type, actual.Index)
+		}
+		if actual.Type == EventStarted {
+			if actual.Result != nil {
+				t.Fatalf("%d-th event should have no result. got: %+v", i, actual.Result)
+			}
+			continue
+		}
+		if actual.Result == nil || actual.Result.Status != e.status {
+			t.Fatalf("%d-th event has wrong result. want=%s, got=%+v", i, e.status, actual.Result)
+		}
+	}
+}
+
+func TestChecker_SetProgress(t *testing.T) {
+	var outStream, errStream bytes.Buffer
+	checker := &Checker{
+		commander: &testCommander{results: []commandResult{{output: "1\n"}}},
+		outStream: &outStream,
+		errStream: &errStream,
+	}
+	var types []EventType
+	checker.SetProgress(func(e Event) {
+		types = append(types, e.Type)
+	})
+
+	checker.CheckAndRender(dummyRawCommand, []Sample{{Input: "0 1\n", Output: "1\n"}})
+	if len(types) != 2 || types[0] != EventStarted || types[1] != EventFinished {
+		t.Fatalf("events wrong. got: %v", types)
+	}
+	if !strings.Contains(outStream.String(), "sample 1: SUCCESS") {
+		t.Fatalf("expect '%s' to contain '%s'", outStream.String(), "sample 1: SUCCESS")
+	}
+}
+
+func TestChecker_CheckResults(t *testing.T) {
+	var outStream, errStream bytes.Buffer
+	checker := &Checker{
+		commander: &testCommander{results: []commandResult{{output: "1\n"}, {output: "99\n"}}},
+		outStream: &outStream,
+		errStream: &errStream,
+	}
+
+	results := checker.CheckResults(dummyRawCommand, []Sample{
+		{Input: "0 1\n", Output: "1\n"},
+		{Input: "1 2\n", Output: "3\n"},
+	})
+	if outStream.String() != "" || errStream.String() != "" {
+		t.Fatalf("nothing should be written. got: %s%s", outStream.String(), errStream.String())
+	}
+	if len(results) != 2 || results[0].Status != StatusSuccess || results[1].Status != StatusFailure || results[1].Actual != "99\n" {
+		t.Fatalf("results wrong. got: %+v", results)
+	}
+}
+
+func TestChecker_Check_delayBetweenSamples(t *testing.T) {
+	var outStream bytes.Buffer
+	c := &Checker{
+		commander: &testCommander{results: []commandResult{{output: "1\n"}, {output: "1\n"}, {output: "1\n"}}},
+		opts:      Options{DelayBetweenSamples: 20 * time.Millisecond},
+		outStream: &outStream,
+	}
+	samples := []Sample{{Output: "1\n"}, {Output: "1\n"}, {Output: "1\n"}}
+
+	start := time.Now()
+	c.Check(dummyRawCommand, samples)
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("samples should be delayed at least 40ms in total. got: %s", elapsed)
+	}
+}
+
+func TestChecker_render_concurrently(t *testing.T) {
+	var outStream bytes.Buffer
+	c := &Checker{outStream: &outStream}
+
+	sample := Sample{Input: "0 1\n", Output: "1\n"}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.render(Result{Index: i, Sample: sample, Status: StatusFailure, Actual: "99\n"})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 50; i++ {
+		expected := fmt.Sprintf("sample %d: FAILURE (0.00s)\ninput:\n0 1\nexpected output:\n1\nactual output:\n99\n", i+1)
+		if !strings.Contains(outStream.String(), expected) {
+			t.Fatalf("expect '%s' to contain '%s'", outStream.String(), expected)
+		}
+	}
+}
+
+func TestChecker_CheckAndRender_jobs(t *testing.T) {
+	cmd := stdinCommander{
+		"1\n": {output: "1\n", delay: 300 * time.Millisecond},
+		"2\n": {output: "2\n", delay: 100 * time.Millisecond},
+		"3\n": {output: "99\n", delay: 200 * time.Millisecond},
+		"4\n": {output: "4\n"},
+	}
+	samples := []Sample{
+		{Input: "1\n", Output: "1\n"},
+		{Input: "2\n", Output: "2\n"},
+		{Input: "3\n", Output: "3\n"},
+		{Input: "4\n", Output: "4\n"},
+	}
+
+	var outStream, errStream bytes.Buffer
+	c := NewChecker(Options{Jobs: 4}, &outStream, &errStream)
+	c.SetCommander(cmd)
+	var finished []int
+	c.SetProgress(func(e Event) {
+		if e.Type == EventFinished {
+			finished = append(finished, e.Index)
+		}
+	})
+
+	start := time.Now()
+	results := c.CheckAndRender(dummyRawCommand, samples)
+	if elapsed := time.Since(start); elapsed > 550*time.Millisecond {
+		t.Fatalf("samples should run concurrently. took %s", elapsed)
+	}
+
+	for i, result := range results {
+		if result.Index != i {
+			t.Fatalf("results should be in the order of the samples. got index %d at %d", result.Index, i)
+		}
+	}
+	if results[2].Status != StatusFailure {
+		t.Fatalf("status of sample 3 wrong. want=%s, got=%s", StatusFailure, results[2].Status)
+	}
+	if fmt.Sprint(finished) != "[0 1 2 3]" {
+		t.Fatalf("finished events should be in the order of the samples. got: %v", finished)
+	}
+
+	output := outStream.String()
+	positions := []int{
+		strings.Index(output, "sample 1: SUCCESS"),
+		strings.Index(output, "sample 2: SUCCESS"),
+		strings.Index(output, "sample 3: FAILURE"),
+		strings.Index(output, "sample 4: SUCCESS"),
+	}
+	for i := 1; i < len(positions); i++ {
+		if positions[i-1] < 0 || positions[i-1] > positions[i] {
+			t.Fatalf("results should be printed in the order of the samples. got:\n%s", output)
+		}
+	}
+}
+
+func TestCheckSamples_failFastWithJobs(t *testing.T) {
+	cmd := stdinCommander{
+		"1\n": {output: "1\n", delay: 100 * time.Millisecond},
+		"2\n": {output: "99\n", delay: 50 * time.Millisecond},
+		"3\n": {output: "3\n"},
+		"4\n": {output: "4\n"},
+	}
+	samples := []Sample{
+		{Input: "1\n", Output: "1\n"},
+		{Input: "2\n", Output: "2\n"},
+		{Input: "3\n", Output: "3\n"},
+		{Input: "4\n", Output: "4\n"},
+	}
+
+	var finished []int
+	results := CheckSamplesWithProgress(cmd, dummyRawCommand, samples, Options{Jobs: 2, FailFast: true}, func(e Event) {
+		if e.Type == EventFinished {
+			finished = append(finished, e.Index)
+		}
+	})
+
+	if len(results) != 2 {
+		t.Fatalf("results should end at the first failure. got: %+v", results)
+	}
+	if results[1].Status != StatusFailure {
+		t.Fatalf("status of sample 2 wrong. want=%s, got=%s", StatusFailure, results[1].Status)
+	}
+	if fmt.Sprint(finished) != "[0 1]" {
+		t.Fatalf("finished events should end at the first failure. got: %v", finished)
+	}
+}
+
+// stdinCommander returns the result for the stdin, which is safe to run concurrently unlike testCommander.
+type stdinCommander map[string]commandResult
+
+func (s stdinCommander) Run(ctx context.Context, command, stdin string) (string, error) {
+	result := s[stdin]
+	time.Sleep(result.delay)
+	return result.output, result.err
+}
+
 type commandResult struct {
 	output string
 	err    error
+	delay  time.Duration
 }
 
 type testCommander struct {
-	index   int
-	results []commandResult
+	index    int
+	results  []commandResult
+	commands []string
+	stdins   []string
 }
 
-func (t *testCommander) Run(command, stdin string) (string, error) {
+func (t *testCommander) Run(ctx context.Context, command, stdin string) (string, error) {
 	if t.index >= len(t.results) {
 		panic("index of testCommander out of range")
 	}
+	t.commands = append(t.commands, command)
+	t.stdins = append(t.stdins, stdin)
 	result := t.results[t.index]
 	t.index++
 
-	return result.output, result.err
+	select {
+	case <-time.After(result.delay):
+		return result.output, result.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
 }
@@ -1,43 +1,144 @@
 package atcoder
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"os"
-	"path"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly"
 )
 
+const maxHeadingSearchDepth = 5
+
+// DefaultProblemListURLPattern is the pattern of the URL of the problem list page, relative to the base URL.
+const DefaultProblemListURLPattern = "/contests/{contest}/tasks"
+
+// contestPlaceholder is replaced with the contest in lower case in the problem list URL pattern.
+const contestPlaceholder = "{contest}"
+
+// DefaultHTTPTimeout is the time a request to AtCoder is given up after, including reading the page.
+const DefaultHTTPTimeout = 30 * time.Second
+
+// ErrHTTPTimeout is the error for the request which got no response within the timeout, e.g.) on a stalled connection.
+var ErrHTTPTimeout = errors.New("request timed out")
+
+// ErrChallenge is the error for the challenge page of Cloudflare, which atctest cannot pass by itself.
+var ErrChallenge = errors.New("AtCoder returned a challenge page which atctest cannot pass. wait a while and try again, log in again, or pass the cookies of your browser with -cookie")
+
+// ErrInvalidCredentials is the error for the login rejected by AtCoder, which is not worth retrying.
+var ErrInvalidCredentials = errors.New("login error: username/password may be wrong")
+
+// loginRetryInterval is multiplied by the number of attempts to wait before retrying login.
+var loginRetryInterval = time.Second
+
+// visitRetryInterval is the wait before the first retry of a page, doubled for each of the following ones.
+var visitRetryInterval = time.Second
+
+// imageElement is the value of a sample element which has no text but an image.
+const imageElement = "\x00image"
+
+// ErrNotTestable is the error for the problems whose sample outputs are not text, e.g.) images.
+var ErrNotTestable = errors.New("the samples of this problem cannot be tested automatically")
+
+// ErrNoSamples is the error for the problem pages which have no samples at all, e.g.) interactive problems.
+var ErrNoSamples = errors.New("no samples found in the problem page")
+
+// sessionUserScreenNameKey is the key of the user in the session cookie. see sessionUserScreenName.
+const sessionUserScreenNameKey = "UserScreenName:"
+
+var challengePageMarkers = []string{
+	"challenge-platform",
+	"cf-chl-",
+	"cf_chl_opt",
+}
+
 type Sample struct {
 	Input  string
 	Output string
+	// Number is the 1-based number of the sample on the problem page, e.g.) 2 for "入力例 2".
+	// 0 means the position in the samples, e.g.) for the samples cached by older versions.
+	Number int `json:",omitempty"`
+}
+
+type Problem struct {
+	// Letter is the label of the problem in the contest, e.g.) "A"
+	Letter string `json:"letter"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
 }
 
 type Client struct {
 	baseURL   string
 	collector *colly.Collector
 
-	useCache     bool
-	cacheDirPath string
+	hooked       bool
+	lastResponse *colly.Response
+
+	loginRetries int
+	// retries is how many times a page is fetched again after network errors or 5xx.
+	retries int
+
+	problemListURLPattern string
+
+	// lang is the language of the sample headings taken from the problem pages.
+	lang Lang
+
+	// maxTotalRetryTime limits retrySpent, the time spent on retries so far.
+	maxTotalRetryTime time.Duration
+	retrySpent        time.Duration
+
+	// minRequestGap is the minimum time between the last request of the previous runs and the first one of this run.
+	minRequestGap time.Duration
+	gapWaited     bool
+
+	// transport is the transport of the proxy given by SetProxy. see baseTransport.
+	transport http.RoundTripper
+	// httpTimeout is the timeout of each request. 0 means no limit.
+	httpTimeout time.Duration
+
+	deadline time.Time
+	// ctx cancels the requests of the method being called. see setContext.
+	ctx context.Context
+
+	verbose bool
+
+	useCache      bool
+	readOnlyCache bool
+	cacheDirPath  string
+	// cacheTTL is the age after which the cache files are ignored. 0 means no limit.
+	cacheTTL time.Duration
 
 	outStream io.Writer
 	errStream io.Writer
 }
 
 func NewClient(baseURL string, useCache bool, cacheDirPath string, outStream, errStream io.Writer) *Client {
-	return &Client{
+	c := &Client{
 		baseURL:      baseURL,
 		collector:    colly.NewCollector(),
+		httpTimeout:  DefaultHTTPTimeout,
 		useCache:     useCache,
 		cacheDirPath: cacheDirPath,
 		outStream:    outStream,
 		errStream:    errStream,
 	}
+	c.collector.WithTransport(&contextTransport{client: c})
+	c.collector.SetRequestTimeout(c.httpTimeout)
+	return c
+}
+
+// CheckReachable checks that the top page of AtCoder can be fetched.
+func (c *Client) CheckReachable() error {
+	return c.visit(c.baseURL)
 }
 
 func (c *Client) IsContestBeingHeld(contestURL string) (bool, error) {
@@ -46,210 +147,571 @@ func (c *Client) IsContestBeingHeld(contestURL string) (bool, error) {
 		beingHeld = true
 	})
 
-	if err := c.collector.Visit(contestURL); err != nil {
-		return false, fmt.Errorf("could not get HTML: %s", contestURL)
+	if err := c.visit(contestURL); err != nil {
+		return false, err
 	}
 
 	return beingHeld, nil
 }
 
 func (c *Client) LogIn(username, password string) error {
+	if c.IsLoggedIn(username) {
+		// e.g.) with the session loaded from a cookie jar
+		return nil
+	}
 	if username == "" || password == "" {
 		return errors.New("you need to provide username and password as command line options to test for the contest being held")
 	}
 
-	var (
-		csrfToken string
-		loginErr  error
-	)
+	var err error
+	for attempt := 0; attempt <= c.loginRetries; attempt++ {
+		if attempt == 0 {
+			err = c.logInOnce(username, password)
+		} else {
+			wait := time.Duration(attempt) * loginRetryInterval
+			if c.retryBudgetExceeded(wait) {
+				return fmt.Errorf("gave up after %s of retries: %w", c.maxTotalRetryTime, err)
+			}
+			_, _ = fmt.Fprintf(c.errStream, "%s. retrying login (%d/%d)\n", err, attempt, c.loginRetries)
+
+			start := time.Now()
+			time.Sleep(wait)
+			err = c.logInOnce(username, password)
+			c.retrySpent += time.Since(start)
+		}
+		if err == nil || errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrDeadlineExceeded) || errors.Is(err, ErrRateLimited) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// SetMaxTotalRetryTime caps the time spent on retries across all the operations of the client. 0 means no limit.
+func (c *Client) SetMaxTotalRetryTime(d time.Duration) {
+	c.maxTotalRetryTime = d
+}
+
+// retryBudgetExceeded reports whether waiting for the next retry would exceed the time allowed for retries.
+func (c *Client) retryBudgetExceeded(wait time.Duration) bool {
+	return c.maxTotalRetryTime > 0 && c.retrySpent+wait > c.maxTotalRetryTime
+}
+
+// SetHTTPTimeout sets the timeout of each request, which is DefaultHTTPTimeout by default. 0 means no limit.
+func (c *Client) SetHTTPTimeout(timeout time.Duration) {
+	c.httpTimeout = timeout
+	c.collector.SetRequestTimeout(timeout)
+}
+
+// SetLoginRetries sets how many times LogIn retries after transient failures.
+func (c *Client) SetLoginRetries(retries int) {
+	c.loginRetries = retries
+}
+
+// SetRetries sets how many times a page is fetched again after network errors or 5xx, with exponential backoff.
+func (c *Client) SetRetries(retries int) {
+	c.retries = retries
+}
+
+// SetLang sets the language of the sample headings taken from the problem pages. LangAuto is the default.
+func (c *Client) SetLang(lang Lang) {
+	c.lang = lang
+}
+
+// SetReadOnlyCache makes the client read the cache files but never write them.
+func (c *Client) SetReadOnlyCache(readOnly bool) {
+	c.readOnlyCache = readOnly
+}
+
+func (c *Client) logInOnce(username, password string) error {
+	var csrfToken string
 	loginURL := c.baseURL + "/login"
 
 	c.collector.OnHTML(`input[name="csrf_token"]`, func(e *colly.HTMLElement) {
-		if csrfToken != "" {
-			return
+		if csrfToken == "" {
+			csrfToken, _ = e.DOM.Attr("value")
 		}
+	})
+
+	if err := c.visit(loginURL); err != nil {
+		return err
+	}
+	if csrfToken == "" {
+		return errors.New("login error: could not find csrf token")
+	}
 
-		csrfToken, _ = e.DOM.Attr("value")
-		reqBody := map[string]string{
-			"username":   username,
-			"password":   password,
-			"csrf_token": csrfToken,
+	reqBody := map[string]string{
+		"username":   username,
+		"password":   password,
+		"csrf_token": csrfToken,
+	}
+	if err := c.beforeRequest(); err != nil {
+		return err
+	}
+	c.logf("POST %s", loginURL)
+	if err := c.collector.Post(loginURL, reqBody); err != nil {
+		if c.deadlineExceeded() {
+			return fmt.Errorf("%w: %s", ErrDeadlineExceeded, loginURL)
 		}
+		if err := c.rateLimitError(loginURL); err != nil {
+			return err
+		}
+		return fmt.Errorf("login error: %s", err)
+	}
+	if !c.IsLoggedIn(username) {
+		return ErrInvalidCredentials
+	}
+
+	return nil
+}
 
-		if err := c.collector.Post(loginURL, reqBody); err != nil {
-			loginErr = fmt.Errorf("login error: %s", err)
+func (c *Client) GetProblemURL(contest, problem string) (string, error) {
+	problem = strings.TrimSpace(problem)
+	var problemURLs []string
+	c.collector.OnHTML(`td > a[href]`, func(e *colly.HTMLElement) {
+		// labels are not always a single letter, e.g.) "Ex" of ABC233
+		if !strings.EqualFold(strings.TrimSpace(e.Text), problem) {
 			return
 		}
-		if !c.isLoggedIn(username) {
-			loginErr = fmt.Errorf("login error: username/password may be wrong")
-			return
+		problemURL := c.baseURL + e.Attr("href")
+		for _, u := range problemURLs {
+			if u == problemURL {
+				return
+			}
 		}
+		problemURLs = append(problemURLs, problemURL)
 	})
 
-	if err := c.collector.Visit(loginURL); err != nil {
-		return fmt.Errorf("could not get HTML: %s", loginURL)
+	problemListURL := c.problemListURL(contest)
+	if err := c.visit(problemListURL); err != nil {
+		return "", err
 	}
 
-	return loginErr
+	switch len(problemURLs) {
+	case 0:
+		return "", fmt.Errorf("could not find problem page for problem '%s' of contest '%s'", problem, contest)
+	case 1:
+		return problemURLs[0], nil
+	default:
+		return "", fmt.Errorf("found multiple problem pages for problem '%s' of contest '%s': %s. specify the problem page with -url", problem, contest, strings.Join(problemURLs, ", "))
+	}
 }
 
-func (c *Client) GetProblemURL(contest, problem string) (string, error) {
-	var problemURL string
-	c.collector.OnHTML(`td > a[href]`, func(e *colly.HTMLElement) {
-		e.DOM.First()
-		if e.Text == strings.ToUpper(problem) {
-			problemURL = c.baseURL + e.Attr("href")
+// SetProblemListURLPattern replaces the pattern of the URL of the problem list page, which has '{contest}' in it.
+// the pattern is relative to the base URL unless it starts with 'http://' or 'https://'.
+func (c *Client) SetProblemListURLPattern(pattern string) error {
+	if !strings.Contains(pattern, contestPlaceholder) {
+		return fmt.Errorf("the problem list URL pattern '%s' must contain %s", pattern, contestPlaceholder)
+	}
+	c.problemListURLPattern = pattern
+	return nil
+}
+
+func (c *Client) problemListURL(contest string) string {
+	pattern := c.problemListURLPattern
+	if pattern == "" {
+		pattern = DefaultProblemListURLPattern
+	}
+	u := strings.Replace(pattern, contestPlaceholder, strings.ToLower(contest), -1)
+	if strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") {
+		return u
+	}
+	return c.baseURL + u
+}
+
+// ListProblems returns the problems of the contest in the order of the problem list page.
+func (c *Client) ListProblems(contest string) ([]Problem, error) {
+	problemListURL := c.problemListURL(contest)
+	if c.useCache {
+		problems, ok := c.getCachedProblems(problemListURL)
+		c.logCache(ok, problemListURL)
+		if ok {
+			return problems, nil
+		}
+	}
+
+	var problems []Problem
+	c.collector.OnHTML(`tbody > tr`, func(e *colly.HTMLElement) {
+		links := e.DOM.Find("td > a[href]")
+		if links.Length() < 2 {
+			return
 		}
+		href, _ := links.First().Attr("href")
+		problems = append(problems, Problem{
+			Letter: strings.TrimSpace(links.First().Text()),
+			Title:  strings.TrimSpace(links.Eq(1).Text()),
+			URL:    c.baseURL + href,
+		})
 	})
 
-	problemListURL := fmt.Sprintf("%s/contests/%s/tasks", c.baseURL, strings.ToLower(contest))
-	if err := c.collector.Visit(problemListURL); err != nil {
-		return "", fmt.Errorf("could not get HTML: %s", problemListURL)
+	if err := c.visit(problemListURL); err != nil {
+		return nil, err
+	}
+	if len(problems) == 0 {
+		return nil, fmt.Errorf("could not find problems of contest '%s'", contest)
 	}
 
-	if problemURL == "" {
-		return "", fmt.Errorf("could not find problem page for problem '%s' of contest '%s'", problem, contest)
+	if err := c.cacheProblems(problemListURL, problems); err != nil {
+		_, _ = io.WriteString(c.errStream, err.Error())
 	}
-	return problemURL, nil
+
+	return problems, nil
 }
 
 func (c *Client) GetSamples(problemURL string) ([]Sample, error) {
-	cacheFilePath := c.cacheFilePath(problemURL)
 	if c.useCache {
-		if samples, ok := c.getCachedSamples(cacheFilePath); ok {
+		samples, ok := c.getCachedSamples(problemURL)
+		c.logCache(ok, problemURL)
+		if ok {
 			return samples, nil
 		}
 	}
 
-	elements, err := c.fetchSampleElements(problemURL)
+	elements, ordered, err := c.fetchSampleElements(problemURL)
 	if err != nil {
 		return nil, err
 	}
 
-	samples, err := c.constructSamples(elements)
+	samples, err := c.buildSamples(elements, ordered)
 	if err != nil {
 		return nil, err
 	}
+	if len(samples)*2 < len(elements) {
+		// the skipped samples may be found next time
+		return samples, nil
+	}
 
-	if err := c.cacheSamples(cacheFilePath, samples); err != nil {
+	if err := c.cacheSamples(problemURL, samples); err != nil {
 		_, _ = io.WriteString(c.errStream, err.Error())
 	}
 
 	return samples, nil
 }
 
-func (c *Client) isLoggedIn(username string) bool {
-	for _, c := range c.collector.Cookies(c.baseURL) {
-		if strings.Contains(c.Value, "UserScreenName%3A"+username) {
-			return true
+// SetCookies sets cookies copied from a browser, e.g.) 'REVEL_SESSION=xxx; cf_clearance=yyy',
+// which lets requests through the challenge page the browser has already passed.
+func (c *Client) SetCookies(rawCookies string) error {
+	cookies := (&http.Request{Header: http.Header{"Cookie": {rawCookies}}}).Cookies()
+	if len(cookies) == 0 {
+		return fmt.Errorf("invalid cookies: %s", rawCookies)
+	}
+	for _, cookie := range cookies {
+		if cookie.Name == "" || cookie.Value == "" {
+			return fmt.Errorf("invalid cookies: %s", rawCookies)
 		}
 	}
-	return false
+	return c.collector.SetCookies(c.baseURL, cookies)
 }
 
-func (c *Client) cacheFilePath(problemURL string) string {
-	escapedURL := strings.Replace(problemURL, "/", "_", -1)
-	filename := fmt.Sprintf("%s.json", escapedURL)
-	return path.Join(c.cacheDirPath, filename)
+// visit visits the page and checks that it is not a challenge page of Cloudflare.
+func (c *Client) visit(pageURL string) error {
+	if !c.hooked {
+		c.collector.AllowURLRevisit = true
+		c.collector.OnRequest(func(r *colly.Request) {
+			c.waitRequestGap()
+			c.recordRequest()
+		})
+		c.collector.OnResponse(func(r *colly.Response) {
+			c.lastResponse = r
+		})
+		c.collector.OnError(func(r *colly.Response, err error) {
+			c.lastResponse = r
+		})
+		c.hooked = true
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.currentContext().Err(); err != nil {
+			return fmt.Errorf("%w: %s", err, pageURL)
+		}
+		err := c.visitOnce(pageURL)
+		if err == nil || !c.isTransient(err) || attempt >= c.retries {
+			return err
+		}
+
+		wait := visitRetryInterval << uint(attempt)
+		if c.retryBudgetExceeded(wait) {
+			return fmt.Errorf("gave up after %s of retries: %w", c.maxTotalRetryTime, err)
+		}
+		_, _ = fmt.Fprintf(c.errStream, "%s. retrying in %s (%d/%d)\n", err, wait, attempt+1, c.retries)
+
+		start := time.Now()
+		select {
+		case <-time.After(wait):
+		case <-c.currentContext().Done():
+		}
+		c.retrySpent += time.Since(start)
+	}
 }
 
-func (c *Client) getCachedSamples(cacheFilePath string) ([]Sample, bool) {
-	_, err := os.Stat(c.cacheDirPath)
-	if err != nil {
-		return nil, false
+// isTransient reports whether the error of visitOnce is worth retrying, i.e. a network error or 5xx.
+func (c *Client) isTransient(err error) bool {
+	if errors.Is(err, ErrChallenge) || errors.Is(err, ErrDeadlineExceeded) || c.currentContext().Err() != nil {
+		return false
 	}
+	return c.lastResponse == nil || c.lastResponse.StatusCode == 0 || c.lastResponse.StatusCode >= http.StatusInternalServerError
+}
 
-	bytes, err := ioutil.ReadFile(cacheFilePath)
-	if err != nil {
-		return nil, false
+func (c *Client) visitOnce(pageURL string) error {
+	if err := c.beforeRequest(); err != nil {
+		return fmt.Errorf("%w: %s", err, pageURL)
 	}
 
-	var samples []Sample
-	if err := json.Unmarshal(bytes, &samples); err != nil {
-		return nil, false
+	c.logf("GET %s", pageURL)
+	c.lastResponse = nil
+	err := c.collector.Visit(pageURL)
+	if c.lastResponse != nil && isChallengePage(c.lastResponse) {
+		return fmt.Errorf("%w: %s", ErrChallenge, pageURL)
+	}
+	if err != nil && c.deadlineExceeded() {
+		return fmt.Errorf("%w: %s", ErrDeadlineExceeded, pageURL)
+	}
+	if ctxErr := c.currentContext().Err(); err != nil && ctxErr != nil {
+		return fmt.Errorf("%w: %s", ctxErr, pageURL)
+	}
+	if err != nil && isTimeout(err) {
+		return fmt.Errorf("%w after %s: %s", ErrHTTPTimeout, c.httpTimeout, pageURL)
+	}
+	if err := c.rateLimitError(pageURL); err != nil {
+		return err
+	}
+	if err != nil && c.lastResponse != nil && c.lastResponse.StatusCode != 0 {
+		return fmt.Errorf("could not get HTML: %s (status %d)", pageURL, c.lastResponse.StatusCode)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get HTML: %s", pageURL)
 	}
 
-	return samples, true
+	return nil
 }
 
-func (c *Client) cacheSamples(cacheFilePath string, samples []Sample) error {
-	_, err := os.Stat(c.cacheDirPath)
-	if os.IsNotExist(err) {
-		if err := os.MkdirAll(c.cacheDirPath, 0777); err != nil {
-			return err
+// isTimeout reports whether the error of the request is caused by its timeout, not by the response.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isChallengePage reports whether the response is the challenge page of Cloudflare.
+// the markers are looked for only in the error responses, since the ordinary pages also load the scripts of Cloudflare,
+// e.g.) /cdn-cgi/challenge-platform/scripts/jsd/main.js.
+func isChallengePage(r *colly.Response) bool {
+	if r.Headers != nil && r.Headers.Get("Cf-Mitigated") == "challenge" {
+		return true
+	}
+	if r.StatusCode != http.StatusForbidden && r.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	for _, marker := range challengePageMarkers {
+		if bytes.Contains(r.Body, []byte(marker)) {
+			return true
 		}
-	} else if err != nil {
-		return err
 	}
+	return false
+}
 
-	bytes, err := json.Marshal(samples)
-	if err != nil {
-		return err
+// IsLoggedIn reports whether the client has the session of the user, e.g.) loaded from a cookie jar, without a request.
+func (c *Client) IsLoggedIn(username string) bool {
+	if username == "" {
+		return false
+	}
+	for _, c := range c.collector.Cookies(c.baseURL) {
+		if name, ok := sessionUserScreenName(c.Value); ok && name == username {
+			return true
+		}
 	}
+	return false
+}
 
-	return ioutil.WriteFile(cacheFilePath, bytes, 0644)
+// sessionUserScreenName returns the user of the session cookie of Revel, the framework of AtCoder,
+// whose value is the signature and the url-encoded fields like 'key:value' joined by NULs,
+// e.g.) 'xxx-%00UserScreenName%3Achokudai%00%00UserName%3Achokudai%00'.
+func sessionUserScreenName(value string) (string, bool) {
+	data, err := url.QueryUnescape(value)
+	if err != nil {
+		return "", false
+	}
+	for _, field := range strings.Split(data, "\x00") {
+		if strings.HasPrefix(field, sessionUserScreenNameKey) {
+			return strings.TrimPrefix(field, sessionUserScreenNameKey), true
+		}
+	}
+	return "", false
 }
 
-func (c *Client) fetchSampleElements(problemURL string) (map[string]string, error) {
+// fetchSampleElements fetches the input/output elements of all the samples,
+// both by their headings and in the order of the page.
+func (c *Client) fetchSampleElements(problemURL string) (map[string]string, []sampleElement, error) {
+	var ordered []sampleElement
+	c.collector.OnHTML(`pre, img`, func(e *colly.HTMLElement) {
+		ordered = addOrderedSample(ordered, e.DOM, c.lang)
+	})
+
 	elements := make(map[string]string)
 	c.collector.OnHTML(`pre`, func(e *colly.HTMLElement) {
-		title := e.DOM.Parent().Find("h3").Text()
-		if strings.HasPrefix(title, "入力例") || strings.HasPrefix(title, "出力例") {
-			titleKey := strings.Replace(title, " ", "", -1)
-			elements[titleKey] = e.Text
-		} else {
-			title := e.DOM.Parent().Parent().Find("h3").Text()
-			if strings.HasPrefix(title, "入力例") || strings.HasPrefix(title, "出力例") {
-				titleKey := strings.Replace(title, " ", "", -1)
-				elements[titleKey] = e.Text
-			}
-		}
+		addSampleText(elements, e.DOM, c.lang)
+	})
+	// a few problems give the sample output as an image instead of text
+	c.collector.OnHTML(`img`, func(e *colly.HTMLElement) {
+		addSampleImage(elements, e.DOM, c.lang)
 	})
 
-	if err := c.collector.Visit(problemURL); err != nil {
-		return nil, fmt.Errorf("could not get HTML: %s", problemURL)
+	if err := c.visit(problemURL); err != nil {
+		return nil, nil, err
 	}
+	return selectLanguage(elements), ordered, nil
+}
+
+// addSampleText adds the text of the pre element to the elements if it is a sample.
+func addSampleText(elements map[string]string, pre *goquery.Selection, lang Lang) {
+	titleKey := normalizeHeading(findHeading(pre))
+	if !isSampleHeading(titleKey, lang) {
+		return
+	}
+	elements[titleKey] = pre.Text()
+}
 
-	return elements, nil
+// addSampleImage marks the sample output which is given as an image.
+func addSampleImage(elements map[string]string, img *goquery.Selection, lang Lang) {
+	titleKey := normalizeHeading(findHeading(img))
+	if !isSampleHeading(titleKey, lang) || !isOutputHeading(titleKey) {
+		return
+	}
+	if strings.TrimSpace(elements[titleKey]) == "" {
+		elements[titleKey] = imageElement
+	}
 }
 
+// buildSamples builds the samples from the elements fetched by fetchSampleElements,
+// pairing them in the order of the page, or by their numbers with constructSamples if they cannot be paired so.
+func (c *Client) buildSamples(elements map[string]string, ordered []sampleElement) ([]Sample, error) {
+	if samples, ok := samplesInOrder(ordered); ok {
+		return samples, nil
+	}
+	return c.constructSamples(elements)
+}
+
+// constructSamples builds the samples from the elements fetched by fetchSampleElements by their numbers.
+// a sample lacking its input or output is skipped with a warning, so that the complete ones can still be tested.
 func (c *Client) constructSamples(elements map[string]string) ([]Sample, error) {
 	if len(elements) == 0 {
-		return nil, errors.New("no sample elements found")
-	}
-	if len(elements)%2 != 0 {
-		return nil, fmt.Errorf("number of sample elements should be even because it consists of pair of input/output. got: %d", len(elements))
+		return nil, ErrNoSamples
 	}
 
-	numSamples := len(elements) / 2
-	samples := make([]Sample, numSamples)
+	h := headingsOf(elements)
 
 	// for html which only has one pair without numbering ["入力例", "出力例"] (without numbering)
-	if numSamples == 1 {
-		if input, ok := elements["入力例"]; ok {
-			if output, ok := elements["出力例"]; ok {
-				samples[0] = Sample{Input: input, Output: output}
-				return samples, nil
+	if input, ok := elements[h.input]; ok {
+		if output, ok := elements[h.output]; ok {
+			if output == imageElement {
+				return nil, fmt.Errorf("%w: %s", ErrNotTestable, imageOutputMessage(h.output))
 			}
+			return []Sample{{Input: input, Output: output, Number: 1}}, nil
 		}
 	}
 
 	// for html which has pairs of samples with numbering ["入力例 1", "出力例 1", "入力例 2", ...]
-	for i := 1; i <= numSamples; i++ {
-		inputKey := fmt.Sprintf("入力例%d", i)
-		outputKey := fmt.Sprintf("出力例%d", i)
+	// the numbers are not always contiguous, e.g.) ["入力例 1", "出力例 1", "入力例 3", "出力例 3"],
+	// so only the numbers of the elements found are paired, and a gap is not taken as a missing sample.
+	var samples []Sample
+	var missing []string
+	images := 0
+	for _, i := range sampleNumbers(elements, h) {
+		inputKey := fmt.Sprintf("%s%d", h.input, i)
+		outputKey := fmt.Sprintf("%s%d", h.output, i)
+
+		input, inputOK := elements[inputKey]
+		output, outputOK := elements[outputKey]
+		switch {
+		case !inputOK:
+			missing = append(missing, fmt.Sprintf("could not find '%s' in HTML", inputKey))
+		case !outputOK:
+			missing = append(missing, fmt.Sprintf("could not find '%s' in HTML", outputKey))
+		case output == imageElement:
+			missing = append(missing, imageOutputMessage(outputKey))
+			images++
+		default:
+			samples = append(samples, Sample{Input: input, Output: output, Number: i})
+		}
+	}
 
-		input, ok := elements[inputKey]
-		if !ok {
-			return nil, fmt.Errorf("could not find '%s' in HTML", inputKey)
+	if len(samples) == 0 {
+		if len(missing) == 0 {
+			return nil, ErrNoSamples
+		}
+		if images > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotTestable, strings.Join(missing, ", "))
 		}
-		output, ok := elements[outputKey]
-		if !ok {
-			return nil, fmt.Errorf("could not find '%s' in HTML", outputKey)
+		return nil, errors.New(strings.Join(missing, ", "))
+	}
+	for _, m := range missing {
+		_, _ = fmt.Fprintf(c.errStream, "[WARNING] %s. the sample is skipped\n", m)
+	}
+	return samples, nil
+}
+
+// sampleNumbers returns the numbers of the samples of which either the input or the output is in the elements, in ascending order.
+func sampleNumbers(elements map[string]string, h sampleHeadings) []int {
+	found := make(map[int]bool)
+	for key := range elements {
+		if n, ok := h.number(key); ok {
+			found[n] = true
 		}
+	}
+	numbers := make([]int, 0, len(found))
+	for n := range found {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+func imageOutputMessage(outputKey string) string {
+	return fmt.Sprintf("'%s' is given as an image, not text", outputKey)
+}
 
-		samples[i-1] = Sample{Input: input, Output: output}
+// isSampleHeading reports whether the heading is strictly of a sample in the language, e.g.) "入力例1" or "出力例",
+// so that the pre elements of the other sections like "配点" or "入力例の説明" are never taken as samples.
+func isSampleHeading(titleKey string, lang Lang) bool {
+	for _, h := range lang.headings() {
+		if titleKey == h.input || titleKey == h.output {
+			return true
+		}
+		if _, ok := h.number(titleKey); ok {
+			return true
+		}
 	}
+	return false
+}
 
-	return samples, nil
+func isOutputHeading(titleKey string) bool {
+	return strings.HasPrefix(titleKey, jaHeadings.output) || strings.HasPrefix(titleKey, enHeadings.output)
+}
+
+// headingReplacer removes the spaces of a heading and converts its full-width digits to half-width ones,
+// since a few problems have the headings like "入力例１" or "入力例　１".
+var headingReplacer = strings.NewReplacer(
+	" ", "", "　", "",
+	"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+	"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+)
+
+// normalizeHeading returns the key of the elements for the heading, e.g.) "入力例 1" and "入力例１" -> "入力例1".
+func normalizeHeading(heading string) string {
+	return headingReplacer.Replace(heading)
+}
+
+// findHeading returns the text of the nearest h3 heading which is a child of the ancestors of the element.
+// e.g.) <section><h3>入力例 1</h3><pre>...</pre></section>
+// e.g.) <div class="part"><h3>入力例 1</h3><section><pre>...</pre></section></div>
+func findHeading(s *goquery.Selection) string {
+	for depth := 0; depth < maxHeadingSearchDepth; depth++ {
+		s = s.Parent()
+		if s.Length() == 0 {
+			return ""
+		}
+		if headings := s.ChildrenFiltered("h3"); headings.Length() > 0 {
+			return headings.First().Text()
+		}
+	}
+	return ""
 }
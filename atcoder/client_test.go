@@ -3,13 +3,16 @@ package atcoder
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gocolly/colly"
 
@@ -26,6 +29,7 @@ func TestClient_IsContestBeingHeld(t *testing.T) {
 
 		inputContestURL string
 
+		mockBaseURL     string
 		mockRequestPath string
 		mockStatusCode  int
 		mockHTMLFile    string
@@ -49,6 +53,24 @@ func TestClient_IsContestBeingHeld(t *testing.T) {
 			mockHTMLFile:    "abc126_not_being_held.html",
 			expected:        false,
 		},
+		{
+			name:            "success-legacy_host_being_held",
+			inputContestURL: "https://apg4b.contest.dummyatcoder.jp",
+			mockBaseURL:     "https://apg4b.contest.dummyatcoder.jp",
+			mockRequestPath: "/",
+			mockStatusCode:  http.StatusOK,
+			mockHTMLFile:    "apg4b_being_held.html",
+			expected:        true,
+		},
+		{
+			name:            "success-legacy_host_not_being_held",
+			inputContestURL: "https://abc126.contest.dummyatcoder.jp",
+			mockBaseURL:     "https://abc126.contest.dummyatcoder.jp",
+			mockRequestPath: "/",
+			mockStatusCode:  http.StatusOK,
+			mockHTMLFile:    "abc126_not_being_held.html",
+			expected:        false,
+		},
 		{
 			name:            "failure-xxx999_not_exist",
 			inputContestURL: dummyBaseURL + "/contests/xxx999",
@@ -65,8 +87,13 @@ func TestClient_IsContestBeingHeld(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			mockBaseURL := test.mockBaseURL
+			if mockBaseURL == "" {
+				mockBaseURL = dummyBaseURL
+			}
+
 			defer gock.Off()
-			gock.New(dummyBaseURL).
+			gock.New(mockBaseURL).
 				Get(test.mockRequestPath).
 				Reply(test.mockStatusCode).
 				AddHeader("Content-Type", "text/html").
@@ -125,6 +152,42 @@ func TestClient_GetProblemURL(t *testing.T) {
 			mockHTMLFile:       "abc001.html",
 			expectedProblemURL: "https://dummyatcoder.jp/contests/abc001/tasks/abc001_1",
 		},
+		{
+			name:               "success-abc001_padded",
+			inputContest:       "abc001",
+			inputProblem:       " d ",
+			mockRequestPath:    "/contests/abc001/tasks",
+			mockStatusCode:     http.StatusOK,
+			mockHTMLFile:       "abc001.html",
+			expectedProblemURL: "https://dummyatcoder.jp/contests/abc001/tasks/abc001_4",
+		},
+		{
+			name:               "success-abc233_ex",
+			inputContest:       "abc233",
+			inputProblem:       "Ex",
+			mockRequestPath:    "/contests/abc233/tasks",
+			mockStatusCode:     http.StatusOK,
+			mockHTMLFile:       "abc233.html",
+			expectedProblemURL: "https://dummyatcoder.jp/contests/abc233/tasks/abc233_h",
+		},
+		{
+			name:               "success-abc233_ex_lowercase",
+			inputContest:       "abc233",
+			inputProblem:       "ex",
+			mockRequestPath:    "/contests/abc233/tasks",
+			mockStatusCode:     http.StatusOK,
+			mockHTMLFile:       "abc233.html",
+			expectedProblemURL: "https://dummyatcoder.jp/contests/abc233/tasks/abc233_h",
+		},
+		{
+			name:            "failure-abc233_e_is_not_ex",
+			inputContest:    "abc233",
+			inputProblem:    "E",
+			mockRequestPath: "/contests/abc233/tasks",
+			mockStatusCode:  http.StatusOK,
+			mockHTMLFile:    "abc233.html",
+			expectedErrMsg:  "could not find problem page for problem 'E'",
+		},
 		{
 			name:               "success-abc124",
 			inputContest:       "abc124",
@@ -170,6 +233,24 @@ func TestClient_GetProblemURL(t *testing.T) {
 			mockHTMLFile:       "tenka1-2019.html",
 			expectedProblemURL: "https://dummyatcoder.jp/contests/tenka1-2019/tasks/tenka1_2019_e",
 		},
+		{
+			name:            "failure-ambiguous",
+			inputContest:    "abc124",
+			inputProblem:    "B",
+			mockRequestPath: "/contests/abc124/tasks",
+			mockStatusCode:  http.StatusOK,
+			mockHTMLFile:    "ambiguous.html",
+			expectedErrMsg:  "found multiple problem pages for problem 'B'",
+		},
+		{
+			name:            "failure-not_found",
+			inputContest:    "abc124",
+			inputProblem:    "Z",
+			mockRequestPath: "/contests/abc124/tasks",
+			mockStatusCode:  http.StatusOK,
+			mockHTMLFile:    "abc124.html",
+			expectedErrMsg:  "could not find problem page for problem 'Z'",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -206,6 +287,153 @@ func TestClient_GetProblemURL(t *testing.T) {
 	}
 }
 
+func TestClient_SetProblemListURLPattern(t *testing.T) {
+	tests := []struct {
+		name                string
+		inputPattern        string
+		inputContest        string
+		expectedProblemList string
+		expectedErrMsg      string
+	}{
+		{
+			name:                "default",
+			inputContest:        "ABC051",
+			expectedProblemList: "https://dummyatcoder.jp/contests/abc051/tasks",
+		},
+		{
+			name:                "relative",
+			inputPattern:        "/contests/{contest}/tasks?lang=en",
+			inputContest:        "ABC051",
+			expectedProblemList: "https://dummyatcoder.jp/contests/abc051/tasks?lang=en",
+		},
+		{
+			name:                "absolute",
+			inputPattern:        "https://{contest}.contest.atcoder.jp/assignments",
+			inputContest:        "ABC051",
+			expectedProblemList: "https://abc051.contest.atcoder.jp/assignments",
+		},
+		{
+			name:           "missing placeholder",
+			inputPattern:   "/contests/%s/tasks",
+			expectedErrMsg: "must contain {contest}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector()}
+			if test.inputPattern != "" {
+				err := c.SetProblemListURLPattern(test.inputPattern)
+				if test.expectedErrMsg != "" {
+					if err == nil {
+						t.Fatal("err should not be nil. got: nil")
+					}
+					if !strings.Contains(err.Error(), test.expectedErrMsg) {
+						t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+			}
+
+			if actual := c.problemListURL(test.inputContest); actual != test.expectedProblemList {
+				t.Fatalf("problem list URL wrong. want=%s, got=%s", test.expectedProblemList, actual)
+			}
+		})
+	}
+}
+
+func TestClient_ListProblems(t *testing.T) {
+	tests := []struct {
+		name string
+
+		inputContest string
+
+		mockRequestPath string
+		mockHTMLFile    string
+
+		expectedProblems []Problem
+		expectedErrMsg   string
+	}{
+		{
+			name:            "success",
+			inputContest:    "ABC124",
+			mockRequestPath: "/contests/abc124/tasks",
+			mockHTMLFile:    "abc124.html",
+			expectedProblems: []Problem{
+				{Letter: "A", Title: "Buttons", URL: dummyBaseURL + "/contests/abc124/tasks/abc124_a"},
+				{Letter: "B", Title: "Great Ocean View", URL: dummyBaseURL + "/contests/abc124/tasks/abc124_b"},
+				{Letter: "C", Title: "Coloring Colorfully", URL: dummyBaseURL + "/contests/abc124/tasks/abc124_c"},
+				{Letter: "D", Title: "Handstand", URL: dummyBaseURL + "/contests/abc124/tasks/abc124_d"},
+			},
+		},
+		{
+			name:            "failure-no_problems",
+			inputContest:    "xxx999",
+			mockRequestPath: "/contests/xxx999/tasks",
+			mockHTMLFile:    "xxx999.html",
+			expectedErrMsg:  "could not find problems of contest 'xxx999'",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if err := os.RemoveAll(dummyCacheDirPath); err != nil {
+					t.Fatalf("failed to remove dummy cache dir: %s", err.Error())
+				}
+			}()
+
+			html, err := ioutil.ReadFile(path.Join("testdata", "problem_list", test.mockHTMLFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer gock.Off()
+			gock.New(dummyBaseURL).
+				Get(test.mockRequestPath).
+				Reply(http.StatusOK).
+				AddHeader("Content-Type", "text/html").
+				BodyString(string(html))
+
+			var errBuff bytes.Buffer
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), useCache: true, cacheDirPath: dummyCacheDirPath, errStream: &errBuff}
+			problems, err := c.ListProblems(test.inputContest)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if len(problems) != len(test.expectedProblems) {
+				t.Fatalf("length of problems wrong. want=%d, got=%d", len(test.expectedProblems), len(problems))
+			}
+			for i, expected := range test.expectedProblems {
+				if problems[i] != expected {
+					t.Fatalf("%d-th problem wrong. want=%+v, got=%+v", i, expected, problems[i])
+				}
+			}
+
+			// the second call is served from the cache without any request
+			cached, err := c.ListProblems(test.inputContest)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if len(cached) != len(problems) {
+				t.Fatalf("length of cached problems wrong. want=%d, got=%d", len(problems), len(cached))
+			}
+		})
+	}
+}
+
 func TestClient_GetSamples(t *testing.T) {
 	tests := []struct {
 		name string
@@ -213,12 +441,14 @@ func TestClient_GetSamples(t *testing.T) {
 		inputProblemURL   string
 		inputUseCache     bool
 		inputCacheDirPath string
+		inputLang         Lang
 
 		mockRequestPath string
 		mockStatusCode  int
 		mockHTMLFile    string
 
 		expectedSamples []Sample
+		expectedWarning string
 		expectedErrMsg  string
 	}{
 		{
@@ -240,6 +470,7 @@ func TestClient_GetSamples(t *testing.T) {
 						"",
 					}, "\n"),
 					Output: "3\n",
+					Number: 1,
 				},
 				{
 					Input: strings.Join([]string{
@@ -248,6 +479,7 @@ func TestClient_GetSamples(t *testing.T) {
 						"",
 					}, "\n"),
 					Output: "3\n",
+					Number: 2,
 				},
 				{
 					Input: strings.Join([]string{
@@ -256,6 +488,7 @@ func TestClient_GetSamples(t *testing.T) {
 						"",
 					}, "\n"),
 					Output: "1\n",
+					Number: 3,
 				},
 			},
 		},
@@ -278,6 +511,7 @@ func TestClient_GetSamples(t *testing.T) {
 						"",
 					}, "\n"),
 					Output: "3\n",
+					Number: 1,
 				},
 				{
 					Input: strings.Join([]string{
@@ -286,6 +520,7 @@ func TestClient_GetSamples(t *testing.T) {
 						"",
 					}, "\n"),
 					Output: "3\n",
+					Number: 2,
 				},
 				{
 					Input: strings.Join([]string{
@@ -294,6 +529,7 @@ func TestClient_GetSamples(t *testing.T) {
 						"",
 					}, "\n"),
 					Output: "1\n",
+					Number: 3,
 				},
 			},
 		},
@@ -315,6 +551,7 @@ func TestClient_GetSamples(t *testing.T) {
 						"",
 					}, "\n"),
 					Output: "5.0\n",
+					Number: 1,
 				},
 				{
 					Input: strings.Join([]string{
@@ -322,6 +559,7 @@ func TestClient_GetSamples(t *testing.T) {
 						"",
 					}, "\n"),
 					Output: "2.0\n",
+					Number: 2,
 				},
 				{
 					Input: strings.Join([]string{
@@ -329,6 +567,7 @@ func TestClient_GetSamples(t *testing.T) {
 						"",
 					}, "\n"),
 					Output: "43257.5\n",
+					Number: 3,
 				},
 			},
 		},
@@ -358,9 +597,112 @@ func TestClient_GetSamples(t *testing.T) {
 						"0",
 						"",
 					}, "\n"),
+					Number: 1,
 				},
 			},
 		},
+		{
+			name: "success-output_missing",
+
+			inputProblemURL:   dummyBaseURL + "/contests/missing/tasks/missing_a",
+			inputUseCache:     false,
+			inputCacheDirPath: dummyCacheDirPath,
+
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/missing/tasks/missing_a",
+			mockHTMLFile:    "missing_output.html",
+
+			expectedSamples: []Sample{
+				{Input: "1 2\n", Output: "3\n", Number: 1},
+				{Input: "0 0\n", Output: "0\n", Number: 3},
+			},
+			expectedWarning: "could not find '出力例2' in HTML",
+		},
+		{
+			name: "failure-output_as_image",
+
+			inputProblemURL:   dummyBaseURL + "/contests/image/tasks/image_a",
+			inputUseCache:     false,
+			inputCacheDirPath: dummyCacheDirPath,
+
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/image/tasks/image_a",
+			mockHTMLFile:    "image_output.html",
+
+			expectedErrMsg: "cannot be tested automatically: '出力例1' is given as an image, not text, '出力例2' is given as an image, not text",
+		},
+		{
+			name: "success-english_only",
+
+			inputProblemURL:   dummyBaseURL + "/contests/english/tasks/english_a",
+			inputCacheDirPath: dummyCacheDirPath,
+
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/english/tasks/english_a",
+			mockHTMLFile:    "english_only.html",
+
+			expectedSamples: []Sample{
+				{Input: "1 2\n", Output: "3\n", Number: 1},
+				{Input: "100 200\n", Output: "300\n", Number: 2},
+			},
+		},
+		{
+			name: "success-bilingual_prefers_japanese",
+
+			inputProblemURL:   dummyBaseURL + "/contests/bilingual/tasks/bilingual_a",
+			inputCacheDirPath: dummyCacheDirPath,
+
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/bilingual/tasks/bilingual_a",
+			mockHTMLFile:    "bilingual.html",
+
+			expectedSamples: []Sample{
+				{Input: "1 2\n", Output: "3\n", Number: 1},
+				{Input: "100 200\n", Output: "300\n", Number: 2},
+			},
+		},
+		{
+			name: "success-bilingual_in_english",
+
+			inputProblemURL:   dummyBaseURL + "/contests/bilingual/tasks/bilingual_a",
+			inputCacheDirPath: dummyCacheDirPath,
+			inputLang:         LangEn,
+
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/bilingual/tasks/bilingual_a",
+			mockHTMLFile:    "bilingual.html",
+
+			expectedSamples: []Sample{
+				{Input: "1 2\n", Output: "three\n", Number: 1},
+				{Input: "100 200\n", Output: "three hundred\n", Number: 2},
+			},
+		},
+		{
+			name: "failure-english_only_in_japanese",
+
+			inputProblemURL:   dummyBaseURL + "/contests/english/tasks/english_a",
+			inputCacheDirPath: dummyCacheDirPath,
+			inputLang:         LangJa,
+
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/english/tasks/english_a",
+			mockHTMLFile:    "english_only.html",
+
+			expectedErrMsg: "no samples found",
+		},
+		{
+			name: "failure-no_samples",
+
+			inputProblemURL:   dummyBaseURL + "/contests/interactive/tasks/interactive_d",
+			inputUseCache:     false,
+			inputCacheDirPath: dummyCacheDirPath,
+
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/interactive/tasks/interactive_d",
+			mockHTMLFile:    "interactive.html",
+
+			expectedErrMsg: "no samples found",
+		},
 		{
 			name: "failure-nonexistent_problem",
 
@@ -411,13 +753,16 @@ func TestClient_GetSamples(t *testing.T) {
 			}
 
 			var errBuff bytes.Buffer
-			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), useCache: test.inputUseCache, cacheDirPath: test.inputCacheDirPath, errStream: &errBuff}
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), useCache: test.inputUseCache, cacheDirPath: test.inputCacheDirPath, lang: test.inputLang, errStream: &errBuff}
 			samples, err := c.GetSamples(test.inputProblemURL)
 			if test.expectedErrMsg == "" {
 				if err != nil {
 					t.Fatalf("err should be nil. got: %s", err.Error())
 				}
-				if errBuff.String() != "" {
+				if !strings.Contains(errBuff.String(), test.expectedWarning) {
+					t.Fatalf("expect '%s' to contain '%s'", errBuff.String(), test.expectedWarning)
+				}
+				if test.expectedWarning == "" && errBuff.String() != "" {
 					t.Fatalf("errStream should be empty. got: %s", errBuff.String())
 				}
 				if len(samples) != len(test.expectedSamples) {
@@ -448,6 +793,7 @@ func TestClient_constructSamples(t *testing.T) {
 		inputElements map[string]string
 
 		expectedSamples []Sample
+		expectedWarning string
 		expectedErrMsg  string
 	}{
 		{
@@ -459,8 +805,8 @@ func TestClient_constructSamples(t *testing.T) {
 				"出力例2": "6\n",
 			},
 			expectedSamples: []Sample{
-				{Input: "1 3 5\n", Output: "9\n"},
-				{Input: "2 4\n", Output: "6\n"},
+				{Input: "1 3 5\n", Output: "9\n", Number: 1},
+				{Input: "2 4\n", Output: "6\n", Number: 2},
 			},
 		},
 		{
@@ -470,7 +816,7 @@ func TestClient_constructSamples(t *testing.T) {
 				"出力例": "9\n",
 			},
 			expectedSamples: []Sample{
-				{Input: "1 3 5\n", Output: "9\n"},
+				{Input: "1 3 5\n", Output: "9\n", Number: 1},
 			},
 		},
 		{
@@ -479,13 +825,81 @@ func TestClient_constructSamples(t *testing.T) {
 			expectedErrMsg: "no sample",
 		},
 		{
-			name: "failure-numbers_of_elements_odd",
+			name: "success-output_missing",
+			inputElements: map[string]string{
+				"入力例1": "1 3 5\n",
+				"出力例1": "9\n",
+				"入力例2": "2 4\n",
+			},
+			expectedSamples: []Sample{
+				{Input: "1 3 5\n", Output: "9\n", Number: 1},
+			},
+			expectedWarning: "could not find '出力例2' in HTML",
+		},
+		{
+			name: "success-middle_input_missing",
+			inputElements: map[string]string{
+				"入力例1": "1 3 5\n",
+				"出力例1": "9\n",
+				"出力例2": "6\n",
+				"入力例3": "7\n",
+				"出力例3": "7\n",
+			},
+			expectedSamples: []Sample{
+				{Input: "1 3 5\n", Output: "9\n", Number: 1},
+				{Input: "7\n", Output: "7\n", Number: 3},
+			},
+			expectedWarning: "could not find '入力例2' in HTML",
+		},
+		{
+			name: "success-non_contiguous_numbers",
+			inputElements: map[string]string{
+				"入力例1": "1 3 5\n",
+				"出力例1": "9\n",
+				"入力例3": "7\n",
+				"出力例3": "7\n",
+			},
+			expectedSamples: []Sample{
+				{Input: "1 3 5\n", Output: "9\n", Number: 1},
+				{Input: "7\n", Output: "7\n", Number: 3},
+			},
+		},
+		{
+			name: "success-numbers_of_multiple_digits",
+			inputElements: map[string]string{
+				"入力例9":   "9\n",
+				"出力例9":   "9\n",
+				"入力例10":  "10\n",
+				"出力例10":  "10\n",
+				"入力例100": "100\n",
+				"出力例100": "100\n",
+			},
+			expectedSamples: []Sample{
+				{Input: "9\n", Output: "9\n", Number: 9},
+				{Input: "10\n", Output: "10\n", Number: 10},
+				{Input: "100\n", Output: "100\n", Number: 100},
+			},
+		},
+		{
+			name: "success-output_as_image",
 			inputElements: map[string]string{
 				"入力例1": "1 3 5\n",
 				"出力例1": "9\n",
 				"入力例2": "2 4\n",
+				"出力例2": imageElement,
+			},
+			expectedSamples: []Sample{
+				{Input: "1 3 5\n", Output: "9\n", Number: 1},
+			},
+			expectedWarning: "'出力例2' is given as an image, not text. the sample is skipped",
+		},
+		{
+			name: "failure-single_output_as_image",
+			inputElements: map[string]string{
+				"入力例": "1 3 5\n",
+				"出力例": imageElement,
 			},
-			expectedErrMsg: "number of sample elements should be even",
+			expectedErrMsg: "cannot be tested automatically: '出力例' is given as an image",
 		},
 		{
 			name: "failure-index_of_入力例_wrong",
@@ -503,15 +917,30 @@ func TestClient_constructSamples(t *testing.T) {
 			},
 			expectedErrMsg: "could not find '出力例1'",
 		},
+		{
+			name: "failure-all_samples_incomplete",
+			inputElements: map[string]string{
+				"入力例1": "1 3 5\n",
+				"出力例3": "9\n",
+			},
+			expectedErrMsg: "could not find '出力例1' in HTML, could not find '入力例3' in HTML",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			c := &Client{}
+			var errBuff bytes.Buffer
+			c := &Client{errStream: &errBuff}
 			samples, err := c.constructSamples(test.inputElements)
 			if test.expectedErrMsg == "" {
 				if err != nil {
 					t.Fatalf("err should be nil. got: %s", err.Error())
 				}
+				if !strings.Contains(errBuff.String(), test.expectedWarning) {
+					t.Fatalf("expect '%s' to contain '%s'", errBuff.String(), test.expectedWarning)
+				}
+				if test.expectedWarning == "" && errBuff.String() != "" {
+					t.Fatalf("errStream should be empty. got: %s", errBuff.String())
+				}
 				if len(samples) != len(test.expectedSamples) {
 					t.Fatalf("length of samples wrong. want=%d, got=%d", len(test.expectedSamples), len(samples))
 				}
@@ -533,6 +962,20 @@ func TestClient_constructSamples(t *testing.T) {
 	}
 }
 
+func TestNormalizeHeading(t *testing.T) {
+	for heading, expected := range map[string]string{
+		"入力例 1":          "入力例1",
+		"入力例１":           "入力例1",
+		"出力例　１０":         "出力例10",
+		"Sample Input 2": "SampleInput2",
+		"入力例":            "入力例",
+	} {
+		if actual := normalizeHeading(heading); actual != expected {
+			t.Fatalf("key of '%s' wrong. want=%s, got=%s", heading, expected, actual)
+		}
+	}
+}
+
 func TestClient_fetchSampleElements(t *testing.T) {
 	tests := []struct {
 		name string
@@ -541,6 +984,7 @@ func TestClient_fetchSampleElements(t *testing.T) {
 
 		mockRequestPath string
 		mockStatusCode  int
+		mockHeader      map[string]string
 		mockHTMLFile    string
 
 		expectedSampleElements map[string]string
@@ -619,6 +1063,73 @@ func TestClient_fetchSampleElements(t *testing.T) {
 				}, "\n"),
 			},
 		},
+		{
+			name:            "success-tabbed_layout",
+			inputProblemURL: dummyBaseURL + "/contests/tabbed/tasks/tabbed_a",
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/tabbed/tasks/tabbed_a",
+			mockHTMLFile:    "tabbed.html",
+			expectedSampleElements: map[string]string{
+				"入力例1": "1 2\n",
+				"出力例1": "3\n",
+				"入力例2": "100 200\n",
+				"出力例2": "300\n",
+			},
+		},
+		{
+			name:            "success-full-width_digits",
+			inputProblemURL: dummyBaseURL + "/contests/fullwidth/tasks/fullwidth_a",
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/fullwidth/tasks/fullwidth_a",
+			mockHTMLFile:    "fullwidth_digits.html",
+			expectedSampleElements: map[string]string{
+				"入力例1":  "1 2\n",
+				"出力例1":  "3\n",
+				"入力例12": "100 200\n",
+				"出力例12": "300\n",
+			},
+		},
+		{
+			name:            "success-score_section_ignored",
+			inputProblemURL: dummyBaseURL + "/contests/score/tasks/score_a",
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/score/tasks/score_a",
+			mockHTMLFile:    "score_section.html",
+			expectedSampleElements: map[string]string{
+				"入力例1": "1 2\n",
+				"出力例1": "3\n",
+			},
+		},
+		{
+			name:            "failure-challenge_page",
+			inputProblemURL: dummyBaseURL + "/contests/abc124/tasks/abc124_b",
+			mockStatusCode:  http.StatusForbidden,
+			mockHTMLFile:    "challenge.html",
+			mockRequestPath: "contests/abc124/tasks/abc124_b",
+			expectedErrMsg:  "challenge page",
+		},
+		{
+			name:            "failure-challenge_page_with_header",
+			inputProblemURL: dummyBaseURL + "/contests/abc124/tasks/abc124_b",
+			mockStatusCode:  http.StatusOK,
+			mockHeader:      map[string]string{"Cf-Mitigated": "challenge"},
+			mockHTMLFile:    "challenge.html",
+			mockRequestPath: "contests/abc124/tasks/abc124_b",
+			expectedErrMsg:  "challenge page",
+		},
+		{
+			name:            "success-cloudflare_script_in_problem_page",
+			inputProblemURL: dummyBaseURL + "/contests/tabbed/tasks/tabbed_a",
+			mockStatusCode:  http.StatusOK,
+			mockRequestPath: "contests/tabbed/tasks/tabbed_a",
+			mockHTMLFile:    "cloudflare_script.html",
+			expectedSampleElements: map[string]string{
+				"入力例1": "1 2\n",
+				"出力例1": "3\n",
+				"入力例2": "100 200\n",
+				"出力例2": "300\n",
+			},
+		},
 		{
 			name:            "failure-nonexistent_problem_URL",
 			inputProblemURL: dummyBaseURL + "/contests/xxx999/tasks/xxx999_x",
@@ -636,14 +1147,17 @@ func TestClient_fetchSampleElements(t *testing.T) {
 			}
 
 			defer gock.Off()
-			gock.New(dummyBaseURL).
+			response := gock.New(dummyBaseURL).
 				Get(test.mockRequestPath).
 				Reply(test.mockStatusCode).
-				AddHeader("Content-Type", "text/html").
-				BodyString(string(html))
+				AddHeader("Content-Type", "text/html")
+			for key, value := range test.mockHeader {
+				response.AddHeader(key, value)
+			}
+			response.BodyString(string(html))
 
 			c := &Client{collector: colly.NewCollector()}
-			sampleElements, err := c.fetchSampleElements(test.inputProblemURL)
+			sampleElements, _, err := c.fetchSampleElements(test.inputProblemURL)
 			if test.expectedErrMsg == "" {
 				if err != nil {
 					t.Fatalf("err should be nil. got: %s", err)
@@ -671,3 +1185,269 @@ func TestClient_fetchSampleElements(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_SetCookies(t *testing.T) {
+	defer gock.Off()
+	gock.New(dummyBaseURL).
+		Get("/contests/abc124").
+		MatchHeader("Cookie", "REVEL_SESSION=session; cf_clearance=clearance").
+		Reply(http.StatusOK).
+		AddHeader("Content-Type", "text/html").
+		BodyString("<html></html>")
+
+	c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector()}
+	if err := c.SetCookies("REVEL_SESSION=session; cf_clearance=clearance"); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if _, err := c.IsContestBeingHeld(dummyBaseURL + "/contests/abc124"); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	if err := c.SetCookies("invalid"); err == nil {
+		t.Fatal("err should not be nil for invalid cookies. got: nil")
+	}
+}
+
+func TestClient_visit_retries(t *testing.T) {
+	type reply struct {
+		statusCode int
+		err        error
+	}
+	tests := []struct {
+		name         string
+		inputRetries int
+		mockReplies  []reply
+
+		expectedErrMsg  string
+		expectedRetries int
+	}{
+		{
+			name:            "success-after_5xx",
+			inputRetries:    3,
+			mockReplies:     []reply{{statusCode: http.StatusBadGateway}, {statusCode: http.StatusServiceUnavailable}, {statusCode: http.StatusOK}},
+			expectedRetries: 2,
+		},
+		{
+			name:            "success-after_network_error",
+			inputRetries:    3,
+			mockReplies:     []reply{{err: errors.New("connection reset by peer")}, {statusCode: http.StatusOK}},
+			expectedRetries: 1,
+		},
+		{
+			name:            "failure-404_not_retried",
+			inputRetries:    3,
+			mockReplies:     []reply{{statusCode: http.StatusNotFound}, {statusCode: http.StatusOK}},
+			expectedErrMsg:  "status 404",
+			expectedRetries: 0,
+		},
+		{
+			name:            "failure-retries_exhausted",
+			inputRetries:    1,
+			mockReplies:     []reply{{statusCode: http.StatusInternalServerError}, {statusCode: http.StatusInternalServerError}, {statusCode: http.StatusOK}},
+			expectedErrMsg:  "status 500",
+			expectedRetries: 1,
+		},
+		{
+			name:            "failure-no_retries",
+			mockReplies:     []reply{{statusCode: http.StatusInternalServerError}, {statusCode: http.StatusOK}},
+			expectedErrMsg:  "status 500",
+			expectedRetries: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func(interval time.Duration) {
+				visitRetryInterval = interval
+			}(visitRetryInterval)
+			visitRetryInterval = 0
+
+			defer gock.Off()
+			for _, reply := range test.mockReplies {
+				r := gock.New(dummyBaseURL).Get("/")
+				if reply.err != nil {
+					r.ReplyError(reply.err)
+					continue
+				}
+				r.Reply(reply.statusCode).AddHeader("Content-Type", "text/html").BodyString("<html></html>")
+			}
+
+			var errBuff bytes.Buffer
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), errStream: &errBuff}
+			c.SetRetries(test.inputRetries)
+			err := c.CheckReachable()
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			} else if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			if retries := strings.Count(errBuff.String(), "retrying"); retries != test.expectedRetries {
+				t.Fatalf("retries wrong. want=%d, got=%d\n%s", test.expectedRetries, retries, errBuff.String())
+			}
+		})
+	}
+}
+
+func TestClient_SetHTTPTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		inputPageURL string
+
+		expectedErr    error
+		expectedErrMsg string
+	}{
+		{
+			name:           "failure-timeout",
+			inputPageURL:   server.URL + "/stalled",
+			expectedErr:    ErrHTTPTimeout,
+			expectedErrMsg: "request timed out after 100ms",
+		},
+		{
+			name:           "failure-not_found",
+			inputPageURL:   server.URL + "/missing",
+			expectedErrMsg: "status 404",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewClient(server.URL, false, "", ioutil.Discard, ioutil.Discard)
+			c.SetHTTPTimeout(100 * time.Millisecond)
+
+			start := time.Now()
+			err := c.visit(test.inputPageURL)
+			if err == nil {
+				t.Fatal("err should not be nil. got: nil")
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("request should be cut at the timeout. took %s", elapsed)
+			}
+			if test.expectedErr != nil && !errors.Is(err, test.expectedErr) {
+				t.Fatalf("err wrong. want=%v, got=%v", test.expectedErr, err)
+			}
+			if test.expectedErr == nil && errors.Is(err, ErrHTTPTimeout) {
+				t.Fatalf("err should not be %v. got: %s", ErrHTTPTimeout, err)
+			}
+			if !strings.Contains(err.Error(), test.expectedErrMsg) {
+				t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestClient_LogIn(t *testing.T) {
+	const loginPage = `<html><body><form method="POST"><input type="hidden" name="csrf_token" value="dummy_token"></form></body></html>`
+	const sessionCookie = "REVEL_SESSION=dummy-%00UserScreenName%3Achokudai%00%00UserName%3Achokudai%00; Path=/"
+
+	type postReply struct {
+		statusCode int
+		setCookie  bool
+	}
+	tests := []struct {
+		name string
+
+		inputRetries           int
+		inputMaxTotalRetryTime time.Duration
+
+		mockPostReplies []postReply
+
+		expectedErr    error
+		expectedErrMsg string
+	}{
+		{
+			name:            "success",
+			mockPostReplies: []postReply{{statusCode: http.StatusOK, setCookie: true}},
+		},
+		{
+			name:            "success-after_transient_failure",
+			inputRetries:    2,
+			mockPostReplies: []postReply{{statusCode: http.StatusInternalServerError}, {statusCode: http.StatusOK, setCookie: true}},
+		},
+		{
+			name:            "failure-invalid_credentials_not_retried",
+			inputRetries:    2,
+			mockPostReplies: []postReply{{statusCode: http.StatusOK}},
+			expectedErr:     ErrInvalidCredentials,
+		},
+		{
+			name:            "failure-retries_exhausted",
+			inputRetries:    1,
+			mockPostReplies: []postReply{{statusCode: http.StatusInternalServerError}, {statusCode: http.StatusInternalServerError}},
+			expectedErrMsg:  "login error",
+		},
+		{
+			name:                   "failure-retry_time_exhausted",
+			inputRetries:           2,
+			inputMaxTotalRetryTime: time.Nanosecond,
+			mockPostReplies:        []postReply{{statusCode: http.StatusInternalServerError}, {statusCode: http.StatusInternalServerError}},
+			expectedErrMsg:         "gave up after 1ns of retries",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func(interval time.Duration) {
+				loginRetryInterval = interval
+			}(loginRetryInterval)
+			loginRetryInterval = 0
+
+			defer gock.Off()
+			for _, reply := range test.mockPostReplies {
+				gock.New(dummyBaseURL).
+					Get("/login").
+					Reply(http.StatusOK).
+					AddHeader("Content-Type", "text/html").
+					BodyString(loginPage)
+				r := gock.New(dummyBaseURL).
+					Post("/login").
+					Reply(reply.statusCode).
+					AddHeader("Content-Type", "text/html")
+				if reply.setCookie {
+					r.AddHeader("Set-Cookie", sessionCookie)
+				}
+				r.BodyString(loginPage)
+			}
+
+			var errBuff bytes.Buffer
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), errStream: &errBuff}
+			c.SetLoginRetries(test.inputRetries)
+			c.SetMaxTotalRetryTime(test.inputMaxTotalRetryTime)
+			err := c.LogIn("chokudai", "password")
+			switch {
+			case test.expectedErr != nil:
+				if !errors.Is(err, test.expectedErr) {
+					t.Fatalf("err wrong. want=%v, got=%v", test.expectedErr, err)
+				}
+			case test.expectedErrMsg != "":
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+			}
+			if !gock.IsDone() {
+				t.Fatal("all the mocked requests should be consumed")
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package atcoder
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var numberPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+type TrailingNewline int
+
+const (
+	// TrailingNewlineLenient accepts the output regardless of whether it ends with a newline, as AtCoder does.
+	TrailingNewlineLenient TrailingNewline = iota
+	// TrailingNewlineRequired rejects the output which does not end with a newline.
+	TrailingNewlineRequired
+	// TrailingNewlineForbidden rejects the output which ends with a newline.
+	TrailingNewlineForbidden
+)
+
+// match reports whether the actual output of the program is accepted as the expected output.
+func match(expected, actual string, opts Options) bool {
+	// the trailing newline is checked first so that it applies to Strict and the empty output too.
+	// printing nothing has no line to end, which is accepted by TrailingNewlineRequired.
+	if !matchTrailingNewline(actual, opts.TrailingNewline) {
+		return false
+	}
+	if opts.Strict {
+		return expected == actual
+	}
+	if strings.TrimSpace(expected) == "" {
+		// the program is expected to print nothing, where the blank lines do not matter either
+		return strings.TrimSpace(actual) == ""
+	}
+
+	expected, actual = normalizeOutput(expected), normalizeOutput(actual)
+	if !opts.tokenwise() {
+		return expected == actual
+	}
+	return matchTokens(expected, actual, opts)
+}
+
+// matchTrailingNewline reports whether the actual output ends with a newline as the mode requires.
+func matchTrailingNewline(actual string, mode TrailingNewline) bool {
+	switch mode {
+	case TrailingNewlineRequired:
+		return actual == "" || strings.HasSuffix(actual, "\n")
+	case TrailingNewlineForbidden:
+		return !strings.HasSuffix(actual, "\n")
+	}
+	return true
+}
+
+// normalizeOutput removes the differences which the judge of AtCoder ignores:
+// CRLF line endings, trailing whitespace of each line and trailing blank lines.
+func normalizeOutput(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+func (o Options) tokenwise() bool {
+	return o.NumericInteger || o.SortLineTokens || o.Round || o.Tolerance > 0
+}
+
+// matchTokens compares the outputs line by line, and each line token by token.
+func matchTokens(expected, actual string, opts Options) bool {
+	expectedLines := splitLines(expected)
+	actualLines := splitLines(actual)
+	if len(expectedLines) != len(actualLines) {
+		return false
+	}
+
+	for i := range expectedLines {
+		expectedTokens := strings.Fields(expectedLines[i])
+		actualTokens := strings.Fields(actualLines[i])
+		if len(expectedTokens) != len(actualTokens) {
+			return false
+		}
+		if opts.SortLineTokens {
+			sortTokens(expectedTokens, opts)
+			sortTokens(actualTokens, opts)
+		}
+		for j := range expectedTokens {
+			if !matchToken(expectedTokens[j], actualTokens[j], opts) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func matchToken(expected, actual string, opts Options) bool {
+	if expected == actual {
+		return true
+	}
+	if opts.NumericInteger {
+		if e, ok := normalizeInteger(expected); ok {
+			// the integers which differ may still be within Tolerance
+			if a, ok := normalizeInteger(actual); ok && e == a {
+				return true
+			}
+		}
+	}
+	if opts.Round {
+		if e, ok := roundNumber(expected, opts.RoundDigits); ok {
+			if a, ok := roundNumber(actual, opts.RoundDigits); ok && e == a {
+				return true
+			}
+		}
+	}
+	if opts.Tolerance > 0 {
+		if e, ok := parseNumber(expected); ok {
+			if a, ok := parseNumber(actual); ok {
+				return withinTolerance(e, a, opts.Tolerance)
+			}
+		}
+	}
+	return false
+}
+
+// withinTolerance reports whether either the absolute or the relative error is within the tolerance, as the judge of AtCoder does.
+func withinTolerance(expected, actual, tolerance float64) bool {
+	diff := math.Abs(expected - actual)
+	return diff <= tolerance || diff <= tolerance*math.Abs(expected)
+}
+
+// parseNumber parses the token as a decimal number, rejecting the words like "Inf" and "NaN" which strconv accepts.
+func parseNumber(token string) (float64, bool) {
+	if !numberPattern.MatchString(token) {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// roundNumber returns the number rounded to the digits after the decimal point, e.g.) ("3.14159", 2) -> "3.14".
+func roundNumber(token string, digits int) (string, bool) {
+	f, ok := parseNumber(token)
+	if !ok {
+		return "", false
+	}
+
+	rounded := strconv.FormatFloat(f, 'f', digits, 64)
+	if strings.Trim(rounded, "-0.") == "" {
+		// -0.00 and 0.00 are the same
+		rounded = strings.TrimPrefix(rounded, "-")
+	}
+	return rounded, true
+}
+
+// sortTokens sorts the tokens so that tokens regarded as the same by matchToken are placed at the same position.
+func sortTokens(tokens []string, opts Options) {
+	key := func(token string) string {
+		if opts.NumericInteger {
+			if normalized, ok := normalizeInteger(token); ok {
+				return normalized
+			}
+		}
+		if opts.Round {
+			if rounded, ok := roundNumber(token, opts.RoundDigits); ok {
+				return rounded
+			}
+		}
+		return token
+	}
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return key(tokens[i]) < key(tokens[j])
+	})
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// normalizeInteger returns the canonical form of an integer of arbitrary length, e.g.) "+007" -> "7", "-0" -> "0".
+func normalizeInteger(token string) (string, bool) {
+	sign := ""
+	digits := token
+	if strings.HasPrefix(digits, "+") || strings.HasPrefix(digits, "-") {
+		if digits[0] == '-' {
+			sign = "-"
+		}
+		digits = digits[1:]
+	}
+	if digits == "" {
+		return "", false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return "0", true
+	}
+	return sign + digits, true
+}
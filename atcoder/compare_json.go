@@ -0,0 +1,111 @@
+package atcoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+type CompareMode int
+
+const (
+	// CompareText compares the outputs as text, with the options like Options.Round.
+	CompareText CompareMode = iota
+	// CompareJSON compares the outputs as JSON values, ignoring whitespace and the order of the keys of objects.
+	CompareJSON
+)
+
+// ParseCompareMode parses the name of the mode like 'json'.
+func ParseCompareMode(name string) (CompareMode, error) {
+	switch strings.ToLower(name) {
+	case "", "text":
+		return CompareText, nil
+	case "json":
+		return CompareJSON, nil
+	default:
+		return CompareText, fmt.Errorf("unknown comparison mode '%s'. specify 'text' or 'json'", name)
+	}
+}
+
+// matchJSON reports whether the outputs are the same sequence of JSON values.
+// it returns an error if either of them is not valid JSON.
+func matchJSON(expected, actual string) (bool, error) {
+	expectedValues, err := parseJSONValues(expected)
+	if err != nil {
+		return false, fmt.Errorf("the expected output is not valid JSON: %s", err)
+	}
+	actualValues, err := parseJSONValues(actual)
+	if err != nil {
+		return false, fmt.Errorf("the output of your program is not valid JSON: %s", err)
+	}
+
+	if len(expectedValues) != len(actualValues) {
+		return false, nil
+	}
+	for i := range expectedValues {
+		if !equalJSON(expectedValues[i], actualValues[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// parseJSONValues parses the whitespace-separated JSON values, e.g.) a value for each line.
+func parseJSONValues(s string) ([]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	// numbers are kept as is to compare large integers exactly
+	decoder.UseNumber()
+
+	var values []interface{}
+	for {
+		var v interface{}
+		if err := decoder.Decode(&v); err == io.EOF {
+			return values, nil
+		} else if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
+
+func equalJSON(expected, actual interface{}) bool {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok || len(e) != len(a) {
+			return false
+		}
+		for key, ev := range e {
+			av, ok := a[key]
+			if !ok || !equalJSON(ev, av) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(e) != len(a) {
+			return false
+		}
+		for i := range e {
+			if !equalJSON(e[i], a[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		a, ok := actual.(json.Number)
+		if !ok {
+			return false
+		}
+		// 1 and 1.0 are the same number
+		er, eok := new(big.Rat).SetString(e.String())
+		ar, aok := new(big.Rat).SetString(a.String())
+		return eok && aok && er.Cmp(ar) == 0
+	default:
+		// strings, booleans and null
+		return expected == actual
+	}
+}
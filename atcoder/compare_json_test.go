@@ -0,0 +1,119 @@
+package atcoder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputExpected  string
+		inputActual    string
+		expected       bool
+		expectedErrMsg string
+	}{
+		{
+			name:          "same-whitespace_and_key_order",
+			inputExpected: `{"a": 1, "b": [1, 2]}` + "\n",
+			inputActual:   `{"b":[1,2],"a":1}`,
+			expected:      true,
+		},
+		{
+			name:          "same-nested",
+			inputExpected: `{"a": {"x": null, "y": true}, "b": "s"}`,
+			inputActual:   `{"b": "s", "a": {"y": true, "x": null}}`,
+			expected:      true,
+		},
+		{
+			name:          "same-number_format",
+			inputExpected: `[1, 0.5, 100]`,
+			inputActual:   `[1.0, 5e-1, 1E2]`,
+			expected:      true,
+		},
+		{
+			name:          "same-values_for_each_line",
+			inputExpected: "{\"a\": 1}\n{\"a\": 2}\n",
+			inputActual:   "{\"a\":1}\n{\"a\":2}\n",
+			expected:      true,
+		},
+		{
+			name:          "different-array_order",
+			inputExpected: `[1, 2]`,
+			inputActual:   `[2, 1]`,
+			expected:      false,
+		},
+		{
+			name:          "different-large_integer",
+			inputExpected: `12345678901234567890`,
+			inputActual:   `12345678901234567891`,
+			expected:      false,
+		},
+		{
+			name:          "different-missing_key",
+			inputExpected: `{"a": 1, "b": 2}`,
+			inputActual:   `{"a": 1, "c": 2}`,
+			expected:      false,
+		},
+		{
+			name:          "different-type",
+			inputExpected: `{"a": 1}`,
+			inputActual:   `{"a": "1"}`,
+			expected:      false,
+		},
+		{
+			name:          "different-number_of_values",
+			inputExpected: "1\n2\n",
+			inputActual:   "1\n",
+			expected:      false,
+		},
+		{
+			name:           "invalid-actual",
+			inputExpected:  `{"a": 1}`,
+			inputActual:    `{"a": 1`,
+			expectedErrMsg: "the output of your program is not valid JSON",
+		},
+		{
+			name:           "invalid-expected",
+			inputExpected:  `Yes`,
+			inputActual:    `{"a": 1}`,
+			expectedErrMsg: "the expected output is not valid JSON",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := matchJSON(test.inputExpected, test.inputActual)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if actual != test.expected {
+				t.Fatalf("match wrong. want=%t, got=%t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseCompareMode(t *testing.T) {
+	for name, expected := range map[string]CompareMode{"": CompareText, "text": CompareText, "JSON": CompareJSON} {
+		actual, err := ParseCompareMode(name)
+		if err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+		if actual != expected {
+			t.Fatalf("mode of '%s' wrong. want=%d, got=%d", name, expected, actual)
+		}
+	}
+	if _, err := ParseCompareMode("xml"); err == nil {
+		t.Fatal("err should not be nil. got: nil")
+	}
+}
@@ -0,0 +1,388 @@
+package atcoder
+
+import (
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputExpected string
+		inputActual   string
+		inputOptions  Options
+		expected      bool
+	}{
+		{
+			name:          "exact-same",
+			inputExpected: "1 2\n3\n",
+			inputActual:   "1 2\n3\n",
+			expected:      true,
+		},
+		{
+			name:          "exact-different",
+			inputExpected: "1 2\n3\n",
+			inputActual:   "1 2\n4\n",
+			expected:      false,
+		},
+		{
+			name:          "trailing_newline_lenient-missing",
+			inputExpected: "3\n",
+			inputActual:   "3",
+			expected:      true,
+		},
+		{
+			name:          "trailing_newline_lenient-extra",
+			inputExpected: "3",
+			inputActual:   "3\n",
+			expected:      true,
+		},
+		{
+			name:          "trailing_newline_lenient-two_newlines",
+			inputExpected: "3\n",
+			inputActual:   "3\n\n",
+			expected:      true,
+		},
+		{
+			name:          "whitespace-trailing_spaces",
+			inputExpected: "1 2\n3\n",
+			inputActual:   "1 2 \n3\t\n",
+			expected:      true,
+		},
+		{
+			name:          "whitespace-crlf",
+			inputExpected: "1 2\n3\n",
+			inputActual:   "1 2\r\n3\r\n",
+			expected:      true,
+		},
+		{
+			name:          "whitespace-leading_spaces",
+			inputExpected: "1 2\n",
+			inputActual:   " 1 2\n",
+			expected:      false,
+		},
+		{
+			name:          "whitespace-blank_line_in_the_middle",
+			inputExpected: "1\n2\n",
+			inputActual:   "1\n\n2\n",
+			expected:      false,
+		},
+		{
+			name:          "strict-same",
+			inputExpected: "3\n",
+			inputActual:   "3\n",
+			inputOptions:  Options{Strict: true},
+			expected:      true,
+		},
+		{
+			name:          "strict-trailing_space",
+			inputExpected: "3\n",
+			inputActual:   "3 \n",
+			inputOptions:  Options{Strict: true},
+			expected:      false,
+		},
+		{
+			name:          "strict-crlf",
+			inputExpected: "3\n",
+			inputActual:   "3\r\n",
+			inputOptions:  Options{Strict: true},
+			expected:      false,
+		},
+		{
+			name:          "strict-missing_newline",
+			inputExpected: "3\n",
+			inputActual:   "3",
+			inputOptions:  Options{Strict: true},
+			expected:      false,
+		},
+		{
+			name:          "trailing_newline_required-present",
+			inputExpected: "3\n",
+			inputActual:   "3\n",
+			inputOptions:  Options{TrailingNewline: TrailingNewlineRequired},
+			expected:      true,
+		},
+		{
+			name:          "trailing_newline_required-missing",
+			inputExpected: "3\n",
+			inputActual:   "3",
+			inputOptions:  Options{TrailingNewline: TrailingNewlineRequired},
+			expected:      false,
+		},
+		{
+			name:          "trailing_newline_forbidden-absent",
+			inputExpected: "3\n",
+			inputActual:   "3",
+			inputOptions:  Options{TrailingNewline: TrailingNewlineForbidden},
+			expected:      true,
+		},
+		{
+			name:          "trailing_newline_forbidden-present",
+			inputExpected: "3\n",
+			inputActual:   "3\n",
+			inputOptions:  Options{TrailingNewline: TrailingNewlineForbidden},
+			expected:      false,
+		},
+		{
+			name:          "trailing_newline_required-strict",
+			inputExpected: "3",
+			inputActual:   "3",
+			inputOptions:  Options{Strict: true, TrailingNewline: TrailingNewlineRequired},
+			expected:      false,
+		},
+		{
+			name:          "trailing_newline_forbidden-strict",
+			inputExpected: "3\n",
+			inputActual:   "3\n",
+			inputOptions:  Options{Strict: true, TrailingNewline: TrailingNewlineForbidden},
+			expected:      false,
+		},
+		{
+			name:          "trailing_newline_forbidden-empty",
+			inputExpected: "\n",
+			inputActual:   "\n",
+			inputOptions:  Options{TrailingNewline: TrailingNewlineForbidden},
+			expected:      false,
+		},
+		{
+			name:          "exact-leading_zero",
+			inputExpected: "7\n",
+			inputActual:   "007\n",
+			expected:      false,
+		},
+		{
+			name:          "numeric_integer-leading_zero",
+			inputExpected: "7\n",
+			inputActual:   "007\n",
+			inputOptions:  Options{NumericInteger: true},
+			expected:      true,
+		},
+		{
+			name:          "numeric_integer-plus_sign",
+			inputExpected: "-3 10\n",
+			inputActual:   "-03 +10\n",
+			inputOptions:  Options{NumericInteger: true},
+			expected:      true,
+		},
+		{
+			name:          "numeric_integer-negative_zero",
+			inputExpected: "0\n",
+			inputActual:   "-000\n",
+			inputOptions:  Options{NumericInteger: true},
+			expected:      true,
+		},
+		{
+			name:          "numeric_integer-large_integer",
+			inputExpected: "123456789012345678901234567890\n",
+			inputActual:   "000123456789012345678901234567890\n",
+			inputOptions:  Options{NumericInteger: true},
+			expected:      true,
+		},
+		{
+			name:          "numeric_integer-different_value",
+			inputExpected: "7\n",
+			inputActual:   "70\n",
+			inputOptions:  Options{NumericInteger: true},
+			expected:      false,
+		},
+		{
+			name:          "numeric_integer-non_integer_token",
+			inputExpected: "Yes\n",
+			inputActual:   "yes\n",
+			inputOptions:  Options{NumericInteger: true},
+			expected:      false,
+		},
+		{
+			name:          "numeric_integer-sign_sign",
+			inputExpected: "+\n",
+			inputActual:   "-\n",
+			inputOptions:  Options{NumericInteger: true},
+			expected:      false,
+		},
+		{
+			name:          "numeric_integer-different_number_of_lines",
+			inputExpected: "1\n2\n",
+			inputActual:   "1 2\n",
+			inputOptions:  Options{NumericInteger: true},
+			expected:      false,
+		},
+		{
+			name:          "numeric_integer-within_tolerance",
+			inputExpected: "100\n",
+			inputActual:   "101\n",
+			inputOptions:  Options{NumericInteger: true, Tolerance: 0.01},
+			expected:      true,
+		},
+		{
+			name:          "numeric_integer-beyond_tolerance",
+			inputExpected: "100\n",
+			inputActual:   "102\n",
+			inputOptions:  Options{NumericInteger: true, Tolerance: 0.01},
+			expected:      false,
+		},
+		{
+			name:          "sort_line_tokens-different_order",
+			inputExpected: "1 2 10\nb a\n",
+			inputActual:   "10 1 2\na  b\n",
+			inputOptions:  Options{SortLineTokens: true},
+			expected:      true,
+		},
+		{
+			name:          "sort_line_tokens-mixed_tokens",
+			inputExpected: "3 apple -1 Banana\n",
+			inputActual:   "Banana -1 apple 3\n",
+			inputOptions:  Options{SortLineTokens: true},
+			expected:      true,
+		},
+		{
+			name:          "sort_line_tokens-tokens_across_lines",
+			inputExpected: "1 2\n3\n",
+			inputActual:   "1\n2 3\n",
+			inputOptions:  Options{SortLineTokens: true},
+			expected:      false,
+		},
+		{
+			name:          "sort_line_tokens-different_tokens",
+			inputExpected: "1 2 3\n",
+			inputActual:   "3 2 2\n",
+			inputOptions:  Options{SortLineTokens: true},
+			expected:      false,
+		},
+		{
+			name:          "sort_line_tokens-with_numeric_integer",
+			inputExpected: "7 10 9\n",
+			inputActual:   "010 9 007\n",
+			inputOptions:  Options{SortLineTokens: true, NumericInteger: true},
+			expected:      true,
+		},
+		{
+			name:          "round-same_after_rounding",
+			inputExpected: "3.14159265\n",
+			inputActual:   "3.1416\n",
+			inputOptions:  Options{Round: true, RoundDigits: 3},
+			expected:      true,
+		},
+		{
+			name:          "round-different_after_rounding",
+			inputExpected: "3.14159265\n",
+			inputActual:   "3.1406\n",
+			inputOptions:  Options{Round: true, RoundDigits: 3},
+			expected:      false,
+		},
+		{
+			name:          "round-integer_and_float",
+			inputExpected: "2 0.5000000000\n",
+			inputActual:   "2.0 0.5\n",
+			inputOptions:  Options{Round: true, RoundDigits: 6},
+			expected:      true,
+		},
+		{
+			name:          "round-negative_zero",
+			inputExpected: "0.000\n",
+			inputActual:   "-0.0001\n",
+			inputOptions:  Options{Round: true, RoundDigits: 2},
+			expected:      true,
+		},
+		{
+			name:          "round-zero_digits",
+			inputExpected: "3\n",
+			inputActual:   "2.9\n",
+			inputOptions:  Options{Round: true, RoundDigits: 0},
+			expected:      true,
+		},
+		{
+			name:          "round-non_numeric_token",
+			inputExpected: "Inf\n",
+			inputActual:   "inf\n",
+			inputOptions:  Options{Round: true, RoundDigits: 2},
+			expected:      false,
+		},
+		{
+			name:          "round-different_number_of_tokens",
+			inputExpected: "1.0 2.0\n",
+			inputActual:   "1.0\n",
+			inputOptions:  Options{Round: true, RoundDigits: 2},
+			expected:      false,
+		},
+		{
+			name:          "tolerance-absolute_error",
+			inputExpected: "0.000001\n",
+			inputActual:   "0.0000015\n",
+			inputOptions:  Options{Tolerance: 1e-6},
+			expected:      true,
+		},
+		{
+			name:          "tolerance-relative_error",
+			inputExpected: "1000000000\n",
+			inputActual:   "1000000500.5\n",
+			inputOptions:  Options{Tolerance: 1e-6},
+			expected:      true,
+		},
+		{
+			name:          "tolerance-too_large_error",
+			inputExpected: "3.141592\n",
+			inputActual:   "3.1416\n",
+			inputOptions:  Options{Tolerance: 1e-6},
+			expected:      false,
+		},
+		{
+			name:          "tolerance-non_numeric_tokens_compared_exactly",
+			inputExpected: "Yes 0.5\n",
+			inputActual:   "yes 0.5000001\n",
+			inputOptions:  Options{Tolerance: 1e-6},
+			expected:      false,
+		},
+		{
+			name:          "tolerance-mixed_tokens",
+			inputExpected: "Yes 0.5\n",
+			inputActual:   "Yes 0.5000001\n",
+			inputOptions:  Options{Tolerance: 1e-6},
+			expected:      true,
+		},
+		{
+			name:          "tolerance-different_number_of_tokens",
+			inputExpected: "1.0 2.0\n",
+			inputActual:   "1.0\n",
+			inputOptions:  Options{Tolerance: 1e-6},
+			expected:      false,
+		},
+		{
+			name:          "empty-nothing_printed",
+			inputExpected: "",
+			inputActual:   "",
+			expected:      true,
+		},
+		{
+			name:          "empty-blank_lines_printed",
+			inputExpected: "\n",
+			inputActual:   "\n\n",
+			expected:      true,
+		},
+		{
+			name:          "empty-newline_required",
+			inputExpected: "",
+			inputActual:   "",
+			inputOptions:  Options{TrailingNewline: TrailingNewlineRequired},
+			expected:      true,
+		},
+		{
+			name:          "empty-something_printed",
+			inputExpected: "\n",
+			inputActual:   "0\n",
+			expected:      false,
+		},
+		{
+			name:          "exact-different_order",
+			inputExpected: "1 2\n",
+			inputActual:   "2 1\n",
+			expected:      false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := match(test.inputExpected, test.inputActual, test.inputOptions)
+			if actual != test.expected {
+				t.Fatalf("match wrong. want=%t, got=%t", test.expected, actual)
+			}
+		})
+	}
+}
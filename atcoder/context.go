@@ -0,0 +1,76 @@
+package atcoder
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// IsContestBeingHeldContext is IsContestBeingHeld whose requests are cancelled with the context, e.g.) on Ctrl-C.
+func (c *Client) IsContestBeingHeldContext(ctx context.Context, contestURL string) (bool, error) {
+	defer c.setContext(ctx)()
+	return c.IsContestBeingHeld(contestURL)
+}
+
+// GetProblemURLContext is GetProblemURL whose requests are cancelled with the context.
+func (c *Client) GetProblemURLContext(ctx context.Context, contest, problem string) (string, error) {
+	defer c.setContext(ctx)()
+	return c.GetProblemURL(contest, problem)
+}
+
+// GetSamplesContext is GetSamples whose requests are cancelled with the context.
+func (c *Client) GetSamplesContext(ctx context.Context, problemURL string) ([]Sample, error) {
+	defer c.setContext(ctx)()
+	return c.GetSamples(problemURL)
+}
+
+// setContext makes the requests cancelled with the context until the returned function is called.
+func (c *Client) setContext(ctx context.Context) func() {
+	c.ctx = ctx
+	return func() {
+		c.ctx = nil
+	}
+}
+
+// currentContext returns the context of the method being called, or the background context.
+func (c *Client) currentContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// contextTransport cancels the requests of the collector with the context of the client,
+// since colly creates the requests without a context.
+type contextTransport struct {
+	client *Client
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the request keeps its own context, which carries the timeout of the http.Client
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.client.currentContext(), cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+
+	res, err := t.client.baseTransport().RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// the body is read after RoundTrip returns, so the context lives until it is closed
+	res.Body = &releaseBody{ReadCloser: res.Body, release: release}
+	return res, nil
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,59 @@
+package atcoder
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_IsContestBeingHeldContext_cancelled(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, false, "", ioutil.Discard, ioutil.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.IsContestBeingHeldContext(ctx, server.URL+"/contests/abc051")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err wrong. want=%v, got=%v", context.Canceled, err)
+	}
+	if requested {
+		t.Fatalf("no request should be sent with the cancelled context")
+	}
+}
+
+func TestClient_GetSamplesContext_duringRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, false, "", ioutil.Discard, ioutil.Discard)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetSamplesContext(ctx, server.URL+"/contests/abc051/tasks/abc051_c")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err wrong. want=%v, got=%v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("request should be cancelled with the context. took %s", elapsed)
+	}
+
+	// the context is only for the call
+	if err := c.currentContext().Err(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+}
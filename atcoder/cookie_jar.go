@@ -0,0 +1,53 @@
+package atcoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// LoadCookieJar loads the cookies saved by SaveCookieJar. a missing file is not an error since it is created on saving.
+// the file holds the session, so it is rejected when other users can access it.
+func (c *Client) LoadCookieJar(jarPath string) error {
+	info, err := os.Stat(jarPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read cookie jar: %s", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("cookie jar %s is accessible by other users. run 'chmod 600 %s'", jarPath, jarPath)
+	}
+
+	bytes, err := ioutil.ReadFile(jarPath)
+	if err != nil {
+		return fmt.Errorf("failed to read cookie jar: %s", err)
+	}
+	var cookies []*http.Cookie
+	if err := json.Unmarshal(bytes, &cookies); err != nil {
+		return fmt.Errorf("failed to parse cookie jar %s: %s", jarPath, err)
+	}
+
+	return c.collector.SetCookies(c.baseURL, cookies)
+}
+
+// SaveCookieJar saves the cookies of the client to the file readable only by the user.
+func (c *Client) SaveCookieJar(jarPath string) error {
+	if err := os.MkdirAll(filepath.Dir(jarPath), 0700); err != nil {
+		return err
+	}
+
+	bytes, err := json.Marshal(c.collector.Cookies(c.baseURL))
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(jarPath, bytes, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the permission of the existing file
+	return os.Chmod(jarPath, 0600)
+}
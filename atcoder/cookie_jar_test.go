@@ -0,0 +1,79 @@
+package atcoder
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gocolly/colly"
+)
+
+func TestClient_cookieJar(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "atctest-cookie-jar")
+	if err != nil {
+		t.Fatalf("failed to create dummy dir: %s", err)
+	}
+	defer os.RemoveAll(dirPath)
+	jarPath := path.Join(dirPath, "accounts", "chokudai.jar")
+
+	c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector()}
+	if err := c.LoadCookieJar(jarPath); err != nil {
+		t.Fatalf("missing cookie jar should not be an error. got: %s", err)
+	}
+	if err := c.SetCookies("REVEL_SESSION=dummy-%00UserScreenName%3Achokudai%00%00UserName%3Achokudai%00"); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := c.SaveCookieJar(jarPath); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	info, err := os.Stat(jarPath)
+	if err != nil {
+		t.Fatalf("cookie jar should be saved. got: %s", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Fatalf("permission of cookie jar wrong. want=%o, got=%o", 0600, perm)
+	}
+
+	loaded := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector()}
+	if err := loaded.LoadCookieJar(jarPath); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := loaded.LogIn("chokudai", ""); err != nil {
+		t.Fatalf("login should be skipped with the saved session. got: %s", err)
+	}
+	if err := loaded.LogIn("other", ""); err == nil {
+		t.Fatal("login as another user should not be skipped")
+	}
+
+	if err := os.Chmod(jarPath, 0644); err != nil {
+		t.Fatalf("failed to change permission: %s", err)
+	}
+	err = loaded.LoadCookieJar(jarPath)
+	if err == nil {
+		t.Fatal("err should not be nil. got: nil")
+	}
+	if !strings.Contains(err.Error(), "accessible by other users") {
+		t.Fatalf("expect '%s' to contain '%s'", err.Error(), "accessible by other users")
+	}
+}
+
+func TestClient_IsLoggedIn(t *testing.T) {
+	c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector()}
+	if err := c.SetCookies("REVEL_SESSION=dummy-%00UserScreenName%3Amui87%00%00UserName%3Amui87%00"); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	for username, expected := range map[string]bool{
+		"mui87":  true,
+		"mui":    false,
+		"mui870": false,
+		"":       false,
+	} {
+		if actual := c.IsLoggedIn(username); actual != expected {
+			t.Fatalf("whether '%s' is logged in wrong. want=%t, got=%t", username, expected, actual)
+		}
+	}
+}
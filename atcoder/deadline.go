@@ -0,0 +1,36 @@
+package atcoder
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrDeadlineExceeded is the error for the run which did not finish by the deadline given to the client and the checker.
+var ErrDeadlineExceeded = errors.New("overall deadline exceeded")
+
+// SetDeadline makes the requests after the time fail with ErrDeadlineExceeded, cutting the ones in flight at the time.
+func (c *Client) SetDeadline(deadline time.Time) {
+	c.deadline = deadline
+}
+
+// beforeRequest limits the request to the rest of the time until the deadline, if it is shorter than the timeout.
+func (c *Client) beforeRequest() error {
+	if c.deadline.IsZero() {
+		return nil
+	}
+	rest := time.Until(c.deadline)
+	if rest <= 0 {
+		return ErrDeadlineExceeded
+	}
+	timeout := c.httpTimeout
+	if timeout == 0 || rest < timeout {
+		timeout = rest
+	}
+	c.collector.SetRequestTimeout(timeout)
+	return nil
+}
+
+// deadlineExceeded reports whether the deadline set by SetDeadline has passed.
+func (c *Client) deadlineExceeded() bool {
+	return !c.deadline.IsZero() && !time.Now().Before(c.deadline)
+}
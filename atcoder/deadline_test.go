@@ -0,0 +1,100 @@
+package atcoder
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestClient_SetDeadline(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputDeadline time.Duration // from now
+		expectedErr   error
+	}{
+		{
+			name:          "before deadline",
+			inputDeadline: time.Minute,
+			expectedErr:   nil,
+		},
+		{
+			name:          "deadline has passed before request",
+			inputDeadline: -time.Second,
+			expectedErr:   ErrDeadlineExceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New(dummyBaseURL).
+				Get("/").
+				Reply(http.StatusOK).
+				AddHeader("Content-Type", "text/html").
+				BodyString("<html></html>")
+
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector()}
+			c.SetDeadline(time.Now().Add(test.inputDeadline))
+
+			err := c.CheckReachable()
+			if test.expectedErr == nil {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				return
+			}
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("err wrong. want=%v, got=%v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestClient_SetDeadline_duringRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(600 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	c := &Client{baseURL: server.URL, collector: colly.NewCollector()}
+	c.SetDeadline(time.Now().Add(100 * time.Millisecond))
+
+	start := time.Now()
+	err := c.CheckReachable()
+	if !errors.Is(err, ErrDeadlineExceeded) {
+		t.Fatalf("err wrong. want=%v, got=%v", ErrDeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Fatalf("request should be cut at the deadline. took %s", elapsed)
+	}
+}
+
+func TestCheckSamples_deadline(t *testing.T) {
+	cmd := &testCommander{
+		results: []commandResult{
+			{output: "1\n"},
+			{output: "3\n", delay: time.Minute},
+		},
+	}
+	samples := []Sample{
+		{Input: "0 1\n", Output: "1\n"},
+		{Input: "1 2\n", Output: "3\n"},
+	}
+
+	start := time.Now()
+	results := CheckSamples(cmd, dummyRawCommand, samples, Options{Deadline: time.Now().Add(100 * time.Millisecond)})
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("samples should be killed at the deadline. took %s", elapsed)
+	}
+	if results[0].Status != StatusSuccess {
+		t.Fatalf("status of sample 1 wrong. want=%s, got=%s", StatusSuccess, results[0].Status)
+	}
+	if results[1].Status != StatusTimeout {
+		t.Fatalf("status of sample 2 wrong. want=%s, got=%s", StatusTimeout, results[1].Status)
+	}
+}
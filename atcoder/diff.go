@@ -0,0 +1,219 @@
+package atcoder
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fatih/color"
+)
+
+// maxDiffCells bounds the size of the LCS table. the lines beyond it are regarded as all changed.
+const maxDiffCells = 4000000
+
+type DiffOptions struct {
+	// Context is the number of unchanged lines shown around each change.
+	Context int
+	// Color colors the lines only in expected red and the lines only in actual green.
+	Color bool
+	// MaxLines is the number of differing lines shown before the rest is omitted with a notice. 0 means no limit.
+	MaxLines int
+}
+
+type diffOp struct {
+	kind byte // ' ' for common lines, '-' for lines only in expected, '+' for lines only in actual
+	line string
+}
+
+// Diff returns the line-by-line difference between expected and actual in the unified format.
+// it returns an empty string when they have the same lines.
+func Diff(expected, actual string, opts DiffOptions) string {
+	ops := diffLines(splitLines(expected), splitLines(actual))
+
+	cutoff, omitted := len(ops), 0
+	if opts.MaxLines > 0 {
+		cutoff, omitted = truncateAt(ops, opts.MaxLines)
+	}
+
+	var buf bytes.Buffer
+	for _, h := range hunks(ops, opts.Context) {
+		if h.start >= cutoff {
+			break
+		}
+		if h.end > cutoff {
+			h.end = cutoff
+		}
+		if !hasChange(ops[h.start:h.end]) {
+			// only the context lines before the omitted lines are left
+			break
+		}
+		writeHunk(&buf, ops, h, opts)
+	}
+	if omitted > 0 {
+		_, _ = fmt.Fprintf(&buf, "... %d more differing lines are omitted\n", omitted)
+	}
+	return buf.String()
+}
+
+func hasChange(ops []diffOp) bool {
+	for _, op := range ops {
+		if op.kind != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateAt returns the index of the op after the first maxLines differing lines, and the number of the differing lines after it.
+func truncateAt(ops []diffOp, maxLines int) (int, int) {
+	cutoff, changed := len(ops), 0
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		changed++
+		if changed == maxLines+1 {
+			cutoff = i
+		}
+	}
+	if changed <= maxLines {
+		return len(ops), 0
+	}
+	return cutoff, changed - maxLines
+}
+
+type hunk struct {
+	start, end int // range of ops
+}
+
+// hunks groups the changes with their context lines, merging the groups which overlap.
+func hunks(ops []diffOp, context int) []hunk {
+	var hs []hunk
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+
+		start, end := i-context, i+context+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if len(hs) > 0 && start <= hs[len(hs)-1].end {
+			hs[len(hs)-1].end = end
+		} else {
+			hs = append(hs, hunk{start: start, end: end})
+		}
+	}
+	return hs
+}
+
+func writeHunk(buf *bytes.Buffer, ops []diffOp, h hunk, opts DiffOptions) {
+	expectedStart, actualStart := 1, 1
+	for _, op := range ops[:h.start] {
+		if op.kind != '+' {
+			expectedStart++
+		}
+		if op.kind != '-' {
+			actualStart++
+		}
+	}
+	expectedLen, actualLen := 0, 0
+	for _, op := range ops[h.start:h.end] {
+		if op.kind != '+' {
+			expectedLen++
+		}
+		if op.kind != '-' {
+			actualLen++
+		}
+	}
+
+	_, _ = fmt.Fprintf(buf, "@@ -%d,%d +%d,%d @@\n", expectedStart, expectedLen, actualStart, actualLen)
+	for _, op := range ops[h.start:h.end] {
+		line := string(op.kind) + op.line
+		switch {
+		case opts.Color && op.kind == '-':
+			_, _ = color.New(color.FgRed).Fprintln(buf, line)
+		case opts.Color && op.kind == '+':
+			_, _ = color.New(color.FgGreen).Fprintln(buf, line)
+		default:
+			_, _ = fmt.Fprintln(buf, line)
+		}
+	}
+}
+
+// diffLines computes the shortest edit from a to b based on their longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	return ops
+}
+
+func diffMiddle(a, b []string) []diffOp {
+	var ops []diffOp
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{kind: '-', line: line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{kind: '+', line: line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{kind: '-', line: a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{kind: '-', line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{kind: '+', line: b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,144 @@
+package atcoder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputExpected string
+		inputActual   string
+		inputOptions  DiffOptions
+		expected      string
+	}{
+		{
+			name:          "same",
+			inputExpected: "1\n2\n3\n",
+			inputActual:   "1\n2\n3\n",
+			expected:      "",
+		},
+		{
+			name:          "changed_line",
+			inputExpected: "1\n2\n3\n",
+			inputActual:   "1\n5\n3\n",
+			inputOptions:  DiffOptions{Context: 1},
+			expected: strings.Join([]string{
+				"@@ -1,3 +1,3 @@",
+				" 1",
+				"-2",
+				"+5",
+				" 3",
+				"",
+			}, "\n"),
+		},
+		{
+			name:          "missing_and_extra_lines",
+			inputExpected: "a\nb\nc\n",
+			inputActual:   "a\nc\nd\n",
+			inputOptions:  DiffOptions{Context: 0},
+			expected: strings.Join([]string{
+				"@@ -2,1 +2,0 @@",
+				"-b",
+				"@@ -4,0 +3,1 @@",
+				"+d",
+				"",
+			}, "\n"),
+		},
+		{
+			name:          "separate_hunks",
+			inputExpected: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			inputActual:   "x\n2\n3\n4\n5\n6\n7\n8\ny\n",
+			inputOptions:  DiffOptions{Context: 1},
+			expected: strings.Join([]string{
+				"@@ -1,2 +1,2 @@",
+				"-1",
+				"+x",
+				" 2",
+				"@@ -8,2 +8,2 @@",
+				" 8",
+				"-9",
+				"+y",
+				"",
+			}, "\n"),
+		},
+		{
+			name:          "max_lines-between_hunks",
+			inputExpected: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			inputActual:   "x\n2\n3\n4\n5\n6\n7\n8\ny\n",
+			inputOptions:  DiffOptions{Context: 1, MaxLines: 2},
+			expected: strings.Join([]string{
+				"@@ -1,2 +1,2 @@",
+				"-1",
+				"+x",
+				" 2",
+				"... 2 more differing lines are omitted",
+				"",
+			}, "\n"),
+		},
+		{
+			name:          "max_lines-in_hunk",
+			inputExpected: "1\n2\n3\n",
+			inputActual:   "x\ny\n3\n",
+			inputOptions:  DiffOptions{Context: 1, MaxLines: 3},
+			expected: strings.Join([]string{
+				"@@ -1,2 +1,1 @@",
+				"-1",
+				"-2",
+				"+x",
+				"... 1 more differing lines are omitted",
+				"",
+			}, "\n"),
+		},
+		{
+			name:          "max_lines-not_reached",
+			inputExpected: "1\n2\n3\n",
+			inputActual:   "1\n5\n3\n",
+			inputOptions:  DiffOptions{Context: 1, MaxLines: 2},
+			expected: strings.Join([]string{
+				"@@ -1,3 +1,3 @@",
+				" 1",
+				"-2",
+				"+5",
+				" 3",
+				"",
+			}, "\n"),
+		},
+		{
+			name:          "merged_hunks",
+			inputExpected: "1\n2\n3\n4\n",
+			inputActual:   "x\n2\n3\ny\n",
+			inputOptions:  DiffOptions{Context: 1},
+			expected: strings.Join([]string{
+				"@@ -1,4 +1,4 @@",
+				"-1",
+				"+x",
+				" 2",
+				" 3",
+				"-4",
+				"+y",
+				"",
+			}, "\n"),
+		},
+		{
+			name:          "trailing_whitespace",
+			inputExpected: "1 2\n",
+			inputActual:   "1 2 \n",
+			expected: strings.Join([]string{
+				"@@ -1,1 +1,1 @@",
+				"-1 2",
+				"+1 2 ",
+				"",
+			}, "\n"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Diff(test.inputExpected, test.inputActual, test.inputOptions)
+			if actual != test.expected {
+				t.Fatalf("diff wrong.\nwant:\n%s\ngot:\n%s", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package atcoder
+
+type EventType int
+
+const (
+	// EventStarted is emitted right before the program runs for a sample.
+	EventStarted EventType = iota
+	// EventFinished is emitted when the result of a sample is available.
+	EventFinished
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventStarted:
+		return "started"
+	case EventFinished:
+		return "finished"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is the progress of checking samples.
+type Event struct {
+	Type EventType
+	// Index is the 0-based position of the sample in the samples given.
+	Index  int
+	Sample Sample
+	// Result is set only for EventFinished.
+	Result *Result
+}
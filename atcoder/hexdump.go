@@ -0,0 +1,86 @@
+package atcoder
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fatih/color"
+)
+
+const (
+	// hexDumpWidth is the number of bytes in a row of HexDump, which keeps both sides within 80 columns.
+	hexDumpWidth = 8
+	// hexDumpRows is the number of rows HexDump shows from the row before the first difference.
+	hexDumpRows = 4
+)
+
+// HexDump shows the bytes of the expected and actual output side by side in hex, around the first byte they differ at,
+// to find the differences invisible as text, e.g.) zero-width spaces or non-breaking spaces.
+// the differing bytes are shown in red. it returns an empty string if the outputs are the same.
+func HexDump(expected, actual string) string {
+	first := firstDifference(expected, actual)
+	if first < 0 {
+		return ""
+	}
+
+	start := first / hexDumpWidth * hexDumpWidth
+	if start >= hexDumpWidth {
+		start -= hexDumpWidth
+	}
+
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "hexdump from the first difference at byte %d (left: expected, right: actual):\n", first)
+	for row := 0; row < hexDumpRows; row++ {
+		offset := start + row*hexDumpWidth
+		if offset >= len(expected) && offset >= len(actual) {
+			break
+		}
+		_, _ = fmt.Fprintf(&buf, "%08x  ", offset)
+		writeHexRow(&buf, expected, actual, offset)
+		_, _ = buf.WriteString("  ")
+		writeHexRow(&buf, actual, expected, offset)
+		_, _ = buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// firstDifference returns the index of the first byte the strings differ at, or -1 if they are the same.
+func firstDifference(a, b string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) == len(b) {
+		return -1
+	}
+	if len(a) < len(b) {
+		return len(a)
+	}
+	return len(b)
+}
+
+// writeHexRow writes the bytes of s from the offset in hex and in ASCII, marking the bytes which differ from other.
+func writeHexRow(buf *bytes.Buffer, s, other string, offset int) {
+	var ascii bytes.Buffer
+	for i := offset; i < offset+hexDumpWidth; i++ {
+		if i >= len(s) {
+			_, _ = buf.WriteString("   ")
+			_ = ascii.WriteByte(' ')
+			continue
+		}
+
+		hex := fmt.Sprintf("%02x", s[i])
+		char := "."
+		if 0x20 <= s[i] && s[i] <= 0x7e {
+			char = string(s[i])
+		}
+		if i >= len(other) || s[i] != other[i] {
+			hex = color.New(color.FgRed).Sprint(hex)
+			char = color.New(color.FgRed).Sprint(char)
+		}
+		_, _ = buf.WriteString(hex + " ")
+		_, _ = ascii.WriteString(char)
+	}
+	_, _ = fmt.Fprintf(buf, "|%s|", ascii.String())
+}
@@ -0,0 +1,64 @@
+package atcoder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHexDump(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputExpected string
+		inputActual   string
+		expected      string
+	}{
+		{
+			name:          "same",
+			inputExpected: "1 2\n",
+			inputActual:   "1 2\n",
+			expected:      "",
+		},
+		{
+			name:          "zero_width_space",
+			inputExpected: "Yes\n",
+			inputActual:   "Yes\u200b\n",
+			expected: strings.Join([]string{
+				"hexdump from the first difference at byte 3 (left: expected, right: actual):",
+				"00000000  59 65 73 0a             |Yes.    |  59 65 73 e2 80 8b 0a    |Yes.... |",
+				"",
+			}, "\n"),
+		},
+		{
+			name:          "missing_trailing_bytes",
+			inputExpected: "abc\n",
+			inputActual:   "abc",
+			expected: strings.Join([]string{
+				"hexdump from the first difference at byte 3 (left: expected, right: actual):",
+				"00000000  61 62 63 0a             |abc.    |  61 62 63                |abc     |",
+				"",
+			}, "\n"),
+		},
+		{
+			name:          "window_from_the_row_before_the_difference",
+			inputExpected: strings.Repeat("0123456789", 10) + "\n",
+			inputActual:   strings.Repeat("0123456789", 5) + "012345678\t" + strings.Repeat("0123456789", 4) + "\n",
+			expected: strings.Join([]string{
+				"hexdump from the first difference at byte 59 (left: expected, right: actual):",
+				"00000030  38 39 30 31 32 33 34 35 |89012345|  38 39 30 31 32 33 34 35 |89012345|",
+				"00000038  36 37 38 39 30 31 32 33 |67890123|  36 37 38 09 30 31 32 33 |678.0123|",
+				"00000040  34 35 36 37 38 39 30 31 |45678901|  34 35 36 37 38 39 30 31 |45678901|",
+				"00000048  32 33 34 35 36 37 38 39 |23456789|  32 33 34 35 36 37 38 39 |23456789|",
+				"",
+			}, "\n"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := HexDump(test.inputExpected, test.inputActual)
+			if actual != test.expected {
+				t.Fatalf("hexdump wrong.\nwant=\n%s\ngot=\n%s", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package atcoder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// InputPlaceholder in the command is replaced with the path of the file of the input with InputModeFile,
+// e.g.) 'python c.py {input}'. the path is quoted for the shell, so the placeholder should not be in quotes.
+const InputPlaceholder = "{input}"
+
+type InputMode int
+
+const (
+	// InputModeStdin gives the input of each sample to the program through stdin.
+	InputModeStdin InputMode = iota
+	// InputModeFile writes the input of each sample to a temporary file, and replaces InputPlaceholder in the command with its path.
+	// nothing is given through stdin.
+	InputModeFile
+)
+
+// ParseInputMode parses the name of the mode like 'file'.
+func ParseInputMode(name string) (InputMode, error) {
+	switch strings.ToLower(name) {
+	case "", "stdin":
+		return InputModeStdin, nil
+	case "file":
+		return InputModeFile, nil
+	default:
+		return InputModeStdin, fmt.Errorf("unknown input mode '%s'. specify 'stdin' or 'file'", name)
+	}
+}
+
+// writeTempFile writes the content to a temporary file, and returns its path with the function removing it.
+// the kind of the content like "input" is used in the name of the file and the errors.
+func writeTempFile(kind, content string) (string, func(), error) {
+	f, err := ioutil.TempFile("", "atctest-"+kind+"-*.txt")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create the %s file: %s", kind, err)
+	}
+	remove := func() { _ = os.Remove(f.Name()) }
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		remove()
+		return "", nil, fmt.Errorf("failed to write the %s file: %s", kind, err)
+	}
+	if err := f.Close(); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("failed to write the %s file: %s", kind, err)
+	}
+	return f.Name(), remove, nil
+}
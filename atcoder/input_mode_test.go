@@ -0,0 +1,41 @@
+package atcoder
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestParseInputMode(t *testing.T) {
+	for name, expected := range map[string]InputMode{"": InputModeStdin, "stdin": InputModeStdin, "File": InputModeFile} {
+		actual, err := ParseInputMode(name)
+		if err != nil {
+			t.Fatalf("err should be nil. got: %s", err)
+		}
+		if actual != expected {
+			t.Fatalf("mode of '%s' wrong. want=%d, got=%d", name, expected, actual)
+		}
+	}
+	if _, err := ParseInputMode("args"); err == nil {
+		t.Fatal("err should not be nil. got: nil")
+	}
+}
+
+func TestWriteTempFile(t *testing.T) {
+	path, remove, err := writeTempFile("input", "1 2\n")
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if string(content) != "1 2\n" {
+		t.Fatalf("content wrong. want=%q, got=%q", "1 2\n", string(content))
+	}
+
+	remove()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("the file should be removed. got: %v", err)
+	}
+}
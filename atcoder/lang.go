@@ -0,0 +1,102 @@
+package atcoder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Lang is the language of the sample headings taken from the problem page.
+type Lang int
+
+const (
+	// LangAuto takes the samples in Japanese, or in English if the page has no Japanese samples.
+	LangAuto Lang = iota
+	// LangJa takes only the samples under "入力例 N" and "出力例 N".
+	LangJa
+	// LangEn takes only the samples under "Sample Input N" and "Sample Output N".
+	LangEn
+)
+
+// ParseLang parses the name of the language like 'en'.
+func ParseLang(name string) (Lang, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return LangAuto, nil
+	case "ja":
+		return LangJa, nil
+	case "en":
+		return LangEn, nil
+	default:
+		return LangAuto, fmt.Errorf("unknown language '%s'. specify 'ja', 'en' or 'auto'", name)
+	}
+}
+
+// sampleHeadings are the headings of the sample input and output in a language, without spaces.
+// the headings are followed by the number of the sample, e.g.) "入力例1", except on the pages with only one sample.
+type sampleHeadings struct {
+	input  string
+	output string
+}
+
+var (
+	jaHeadings = sampleHeadings{input: "入力例", output: "出力例"}
+	enHeadings = sampleHeadings{input: "SampleInput", output: "SampleOutput"}
+)
+
+// headings returns the headings of the samples taken in the language.
+func (l Lang) headings() []sampleHeadings {
+	switch l {
+	case LangJa:
+		return []sampleHeadings{jaHeadings}
+	case LangEn:
+		return []sampleHeadings{enHeadings}
+	default:
+		return []sampleHeadings{jaHeadings, enHeadings}
+	}
+}
+
+// has reports whether the element key is of the headings, e.g.) "入力例2" for jaHeadings.
+func (h sampleHeadings) has(key string) bool {
+	return strings.HasPrefix(key, h.input) || strings.HasPrefix(key, h.output)
+}
+
+// number returns the number of the element key, e.g.) "入力例2" -> 2.
+func (h sampleHeadings) number(key string) (int, bool) {
+	for _, prefix := range []string{h.input, h.output} {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+		return n, err == nil && n > 0
+	}
+	return 0, false
+}
+
+// headingsOf returns the headings of the language the elements are in, preferring Japanese if they are in both.
+func headingsOf(elements map[string]string) sampleHeadings {
+	for key := range elements {
+		if jaHeadings.has(key) {
+			return jaHeadings
+		}
+	}
+	for key := range elements {
+		if enHeadings.has(key) {
+			return enHeadings
+		}
+	}
+	return jaHeadings
+}
+
+// selectLanguage leaves the elements only in the language of headingsOf,
+// since the pages show the same samples both in Japanese and in English.
+func selectLanguage(elements map[string]string) map[string]string {
+	h := headingsOf(elements)
+	selected := make(map[string]string)
+	for key, element := range elements {
+		if h.has(key) {
+			selected[key] = element
+		}
+	}
+	return selected
+}
@@ -0,0 +1,109 @@
+package atcoder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// GetSamplesFromFile reads the samples from the problem page saved by a browser,
+// either as an HTML file, e.g.) "Web Page, Complete", or as an MHTML file, e.g.) "Web Page, Single File".
+func (c *Client) GetSamplesFromFile(pagePath string) ([]Sample, error) {
+	content, err := ioutil.ReadFile(pagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the page: %s", err)
+	}
+
+	if isMHTML(pagePath, content) {
+		content, err = extractHTMLFromMHTML(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the MHTML file %s: %s", pagePath, err)
+		}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the page %s: %s", pagePath, err)
+	}
+
+	elements := make(map[string]string)
+	var ordered []sampleElement
+	doc.Find(`pre, img`).Each(func(_ int, s *goquery.Selection) {
+		ordered = addOrderedSample(ordered, s, c.lang)
+	})
+	doc.Find(`pre`).Each(func(_ int, s *goquery.Selection) {
+		addSampleText(elements, s, c.lang)
+	})
+	doc.Find(`img`).Each(func(_ int, s *goquery.Selection) {
+		addSampleImage(elements, s, c.lang)
+	})
+
+	return c.buildSamples(selectLanguage(elements), ordered)
+}
+
+// isMHTML detects the MHTML file by its extension, or by its header for the files saved with another extension.
+func isMHTML(pagePath string, content []byte) bool {
+	switch strings.ToLower(filepath.Ext(pagePath)) {
+	case ".mht", ".mhtml":
+		return true
+	case ".html", ".htm":
+		return false
+	}
+	head := content
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	return bytes.Contains(bytes.ToLower(head), []byte("multipart/related"))
+}
+
+// extractHTMLFromMHTML returns the first HTML part of the MHTML, which is the page itself.
+func extractHTMLFromMHTML(content []byte) ([]byte, error) {
+	msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(content)))
+	if err != nil {
+		return nil, err
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("unexpected content type '%s'", mediaType)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errors.New("no HTML part found")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		partType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err != nil || partType != "text/html" {
+			continue
+		}
+		return ioutil.ReadAll(decodePart(part))
+	}
+}
+
+// decodePart decodes the part by its Content-Transfer-Encoding.
+// quoted-printable, which Chrome uses, is decoded by multipart.Part itself.
+func decodePart(part *multipart.Part) io.Reader {
+	if strings.EqualFold(part.Header.Get("Content-Transfer-Encoding"), "base64") {
+		return base64.NewDecoder(base64.StdEncoding, part)
+	}
+	return part
+}
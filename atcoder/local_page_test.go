@@ -0,0 +1,121 @@
+package atcoder
+
+import (
+	"bytes"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_GetSamplesFromFile(t *testing.T) {
+	abc124bSamples := []Sample{
+		{Input: "4\n6 5 6 8\n", Output: "3\n", Number: 1},
+		{Input: "5\n4 5 3 5 4\n", Output: "3\n", Number: 2},
+		{Input: "5\n9 5 6 8 4\n", Output: "1\n", Number: 3},
+	}
+
+	tests := []struct {
+		name            string
+		inputPagePath   string
+		expectedSamples []Sample
+		expectedErrMsg  string
+	}{
+		{
+			name:            "success-html",
+			inputPagePath:   path.Join("testdata", "problem", "abc124b.html"),
+			expectedSamples: abc124bSamples,
+		},
+		{
+			name:            "success-mhtml_quoted_printable",
+			inputPagePath:   path.Join("testdata", "saved_page", "abc124b.mhtml"),
+			expectedSamples: abc124bSamples,
+		},
+		{
+			name:            "success-mhtml_base64",
+			inputPagePath:   path.Join("testdata", "saved_page", "abc124b_base64.mht"),
+			expectedSamples: abc124bSamples,
+		},
+		{
+			name:          "success-unnumbered_headings",
+			inputPagePath: path.Join("testdata", "problem", "unnumbered.html"),
+			expectedSamples: []Sample{
+				{Input: "1 2\n", Output: "3\n", Number: 1},
+				{Input: "100 200\n", Output: "300\n", Number: 2},
+			},
+		},
+		{
+			name:           "failure-not_found",
+			inputPagePath:  path.Join("testdata", "saved_page", "xxx.mhtml"),
+			expectedErrMsg: "failed to read the page",
+		},
+		{
+			name:           "failure-no_samples",
+			inputPagePath:  path.Join("testdata", "problem", "xxx999x.html"),
+			expectedErrMsg: "no samples found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Client{errStream: &bytes.Buffer{}}
+			samples, err := c.GetSamplesFromFile(test.inputPagePath)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if !reflect.DeepEqual(samples, test.expectedSamples) {
+				t.Fatalf("samples wrong. want=%+v, got=%+v", test.expectedSamples, samples)
+			}
+		})
+	}
+}
+
+func TestIsMHTML(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputPagePath string
+		inputContent  string
+		expected      bool
+	}{
+		{
+			name:          "mhtml extension",
+			inputPagePath: "abc124b.mhtml",
+			expected:      true,
+		},
+		{
+			name:          "html extension",
+			inputPagePath: "abc124b.html",
+			inputContent:  "<html>multipart/related</html>",
+			expected:      false,
+		},
+		{
+			name:          "mhtml header with another extension",
+			inputPagePath: "abc124b.saved",
+			inputContent:  "MIME-Version: 1.0\r\nContent-Type: multipart/related;\r\n",
+			expected:      true,
+		},
+		{
+			name:          "html with another extension",
+			inputPagePath: "abc124b.saved",
+			inputContent:  "<!DOCTYPE html>\n<html></html>",
+			expected:      false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := isMHTML(test.inputPagePath, []byte(test.inputContent)); actual != test.expected {
+				t.Fatalf("isMHTML wrong. want=%t, got=%t", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package atcoder
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SetProxy makes the requests go through the proxy instead of the one given by HTTP_PROXY, HTTPS_PROXY and NO_PROXY,
+// which are honored by default. e.g.) "http://proxy.example.com:8080", "socks5://127.0.0.1:1080"
+func (c *Client) SetProxy(rawURL string) error {
+	proxyURL, err := parseProxyURL(rawURL)
+	if err != nil {
+		return err
+	}
+	c.transport = newProxyTransport(proxyURL)
+	return nil
+}
+
+// newProxyTransport returns a copy of http.DefaultTransport going through the proxy,
+// which keeps its timeouts, idle connections and HTTP/2 so that setting a proxy changes nothing else.
+func newProxyTransport(proxyURL *url.URL) *http.Transport {
+	transport := &http.Transport{}
+	// http.DefaultTransport may be replaced, e.g.) by a mock in tests
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return transport
+}
+
+// parseProxyURL parses the URL of the proxy, which needs a scheme supported by net/http and a host.
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url '%s': %s", rawURL, err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy url '%s': the scheme must be http, https or socks5", rawURL)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url '%s': no host is given", rawURL)
+	}
+	return proxyURL, nil
+}
+
+// baseTransport returns the transport the requests are sent with,
+// http.DefaultTransport which honors the proxy environment variables unless SetProxy is called.
+func (c *Client) baseTransport() http.RoundTripper {
+	if c.transport == nil {
+		return http.DefaultTransport
+	}
+	return c.transport
+}
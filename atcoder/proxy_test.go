@@ -0,0 +1,106 @@
+package atcoder
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_SetProxy(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputURL string
+
+		expectedErrMsg string
+	}{
+		{
+			name:     "success-http",
+			inputURL: "http://proxy.example.com:8080",
+		},
+		{
+			name:     "success-socks5",
+			inputURL: "socks5://127.0.0.1:1080",
+		},
+		{
+			name:           "failure-no scheme",
+			inputURL:       "proxy.example.com:8080",
+			expectedErrMsg: "the scheme must be http, https or socks5",
+		},
+		{
+			name:           "failure-unsupported scheme",
+			inputURL:       "ftp://proxy.example.com",
+			expectedErrMsg: "the scheme must be http, https or socks5",
+		},
+		{
+			name:           "failure-no host",
+			inputURL:       "http://",
+			expectedErrMsg: "no host is given",
+		},
+		{
+			name:           "failure-malformed",
+			inputURL:       "http://proxy.example.com:port",
+			expectedErrMsg: "invalid proxy url",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewClient(dummyBaseURL, false, "", ioutil.Discard, ioutil.Discard)
+			err := c.SetProxy(test.inputURL)
+			if test.expectedErrMsg == "" {
+				if err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("err should not be nil. got: nil")
+			}
+			if !strings.Contains(err.Error(), test.expectedErrMsg) {
+				t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+			}
+		})
+	}
+}
+
+func TestNewProxyTransport(t *testing.T) {
+	proxyURL, err := parseProxyURL("http://proxy.example.com:8080")
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	transport := newProxyTransport(proxyURL)
+
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	if transport.TLSHandshakeTimeout != defaultTransport.TLSHandshakeTimeout || transport.IdleConnTimeout != defaultTransport.IdleConnTimeout {
+		t.Fatalf("timeouts should be kept. want=(%s, %s), got=(%s, %s)", defaultTransport.TLSHandshakeTimeout, defaultTransport.IdleConnTimeout, transport.TLSHandshakeTimeout, transport.IdleConnTimeout)
+	}
+	if transport.DialContext == nil || !transport.ForceAttemptHTTP2 {
+		t.Fatal("the dialer and HTTP/2 should be kept")
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://atcoder.jp", nil)
+	if got, err := transport.Proxy(req); err != nil || got.String() != proxyURL.String() {
+		t.Fatalf("proxy wrong. want=%s, got=%v (%v)", proxyURL, got, err)
+	}
+}
+
+func TestClient_SetProxy_request(t *testing.T) {
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer proxy.Close()
+
+	c := NewClient("http://atcoder.invalid", false, "", ioutil.Discard, ioutil.Discard)
+	if err := c.SetProxy(proxy.URL); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if err := c.CheckReachable(); err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if proxiedURL != "http://atcoder.invalid" && proxiedURL != "http://atcoder.invalid/" {
+		t.Fatalf("request should go through the proxy. got: '%s'", proxiedURL)
+	}
+}
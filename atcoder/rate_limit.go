@@ -0,0 +1,46 @@
+package atcoder
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRateLimited is the error for 429 Too Many Requests, returned when atctest is run too often in a short time.
+var ErrRateLimited = errors.New("rate limited by AtCoder. please wait a while before running atctest again")
+
+// rateLimitError returns ErrRateLimited with the time to wait if the last response is 429, or nil.
+func (c *Client) rateLimitError(pageURL string) error {
+	if c.lastResponse == nil || c.lastResponse.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	if c.lastResponse.Headers != nil {
+		if wait := retryAfter(c.lastResponse.Headers.Get("Retry-After"), time.Now()); wait != "" {
+			return fmt.Errorf("%w (retry after %s): %s", ErrRateLimited, wait, pageURL)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrRateLimited, pageURL)
+}
+
+// retryAfter formats the value of the Retry-After header, given either in seconds or as a date.
+// it returns an empty string if the value is missing or malformed.
+func retryAfter(value string, now time.Time) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return (time.Duration(seconds) * time.Second).String()
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now).Round(time.Second)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait.String()
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package atcoder
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestClient_visit_rateLimited(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockRetryAfter string
+		expectedErrMsg string
+	}{
+		{
+			name:           "with retry-after",
+			mockRetryAfter: "120",
+			expectedErrMsg: "(retry after 2m0s)",
+		},
+		{
+			name:           "without retry-after",
+			expectedErrMsg: "please wait a while",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer gock.Off()
+			r := gock.New(dummyBaseURL).Get("/").Reply(http.StatusTooManyRequests)
+			if test.mockRetryAfter != "" {
+				r.AddHeader("Retry-After", test.mockRetryAfter)
+			}
+			gock.New(dummyBaseURL).Get("/").Reply(http.StatusOK).BodyString("<html></html>")
+
+			c := NewClient(dummyBaseURL, false, "", ioutil.Discard, ioutil.Discard)
+			c.SetRetries(3)
+			err := c.CheckReachable()
+			if !errors.Is(err, ErrRateLimited) {
+				t.Fatalf("err wrong. want=%v, got=%v", ErrRateLimited, err)
+			}
+			if !strings.Contains(err.Error(), test.expectedErrMsg) {
+				t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+			}
+			if gock.IsDone() {
+				t.Fatal("429 should not be retried")
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "seconds", input: "30", expected: "30s"},
+		{name: "date", input: "Wed, 01 May 2019 12:05:00 GMT", expected: "5m0s"},
+		{name: "past date", input: "Wed, 01 May 2019 11:00:00 GMT", expected: "0s"},
+		{name: "empty", input: "", expected: ""},
+		{name: "malformed", input: "soon", expected: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := retryAfter(test.input, now); got != test.expected {
+				t.Fatalf("retryAfter wrong. want='%s', got='%s'", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package atcoder
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// lastRequestFileName is the name of the file in the cache directory which keeps the time of the last request to AtCoder.
+const lastRequestFileName = "last_request"
+
+// SetMinRequestGap makes the first request of the client wait until the duration has passed
+// since the last request of the previous runs, so that successive runs do not burst requests. 0 disables it.
+func (c *Client) SetMinRequestGap(d time.Duration) {
+	c.minRequestGap = d
+}
+
+// waitRequestGap waits before the first request of the client for the rest of the gap since the last request.
+// the wait ends early when the context of the method being called is done, e.g.) by Ctrl-C or -deadline.
+func (c *Client) waitRequestGap() {
+	if c.minRequestGap <= 0 || c.gapWaited {
+		return
+	}
+	c.gapWaited = true
+
+	bytes, err := ioutil.ReadFile(path.Join(c.cacheDirPath, lastRequestFileName))
+	if err != nil {
+		return
+	}
+	last, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(bytes)))
+	if err != nil {
+		return
+	}
+	if wait := c.minRequestGap - time.Since(last); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-c.currentContext().Done():
+		}
+	}
+}
+
+// recordRequest records the time of the request for the next runs.
+func (c *Client) recordRequest() {
+	if c.minRequestGap <= 0 || c.readOnlyCache {
+		return
+	}
+	if err := os.MkdirAll(c.cacheDirPath, 0777); err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(path.Join(c.cacheDirPath, lastRequestFileName), []byte(time.Now().Format(time.RFC3339Nano)), 0644)
+}
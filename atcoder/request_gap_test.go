@@ -0,0 +1,139 @@
+package atcoder
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestClient_SetMinRequestGap(t *testing.T) {
+	tests := []struct {
+		name             string
+		inputGap         time.Duration
+		inputLastRequest time.Duration // how long ago the last request was made. 0 means no record.
+		expectedMinWait  time.Duration
+		expectedMaxWait  time.Duration
+		expectedRecorded bool
+	}{
+		{
+			name:             "wait for the rest of the gap",
+			inputGap:         300 * time.Millisecond,
+			inputLastRequest: 100 * time.Millisecond,
+			expectedMinWait:  150 * time.Millisecond,
+			expectedMaxWait:  time.Second,
+			expectedRecorded: true,
+		},
+		{
+			name:             "gap has already passed",
+			inputGap:         300 * time.Millisecond,
+			inputLastRequest: time.Hour,
+			expectedMaxWait:  150 * time.Millisecond,
+			expectedRecorded: true,
+		},
+		{
+			name:             "no record",
+			inputGap:         300 * time.Millisecond,
+			expectedMaxWait:  150 * time.Millisecond,
+			expectedRecorded: true,
+		},
+		{
+			name:             "disabled",
+			inputGap:         0,
+			inputLastRequest: 100 * time.Millisecond,
+			expectedMaxWait:  150 * time.Millisecond,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cacheDirPath, err := ioutil.TempDir("", "atctest-request-gap")
+			if err != nil {
+				t.Fatalf("failed to create dummy cache dir: %s", err)
+			}
+			defer os.RemoveAll(cacheDirPath)
+			lastRequestPath := path.Join(cacheDirPath, lastRequestFileName)
+
+			if test.inputLastRequest > 0 {
+				last := time.Now().Add(-test.inputLastRequest).Format(time.RFC3339Nano)
+				if err := ioutil.WriteFile(lastRequestPath, []byte(last), 0644); err != nil {
+					t.Fatalf("failed to write dummy last request: %s", err)
+				}
+			}
+
+			defer gock.Off()
+			gock.New(dummyBaseURL).
+				Get("/").
+				Times(2).
+				Reply(http.StatusOK).
+				AddHeader("Content-Type", "text/html").
+				BodyString("<html></html>")
+
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), cacheDirPath: cacheDirPath}
+			c.SetMinRequestGap(test.inputGap)
+
+			start := time.Now()
+			if err := c.CheckReachable(); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			// only the first request waits
+			if err := c.CheckReachable(); err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			elapsed := time.Since(start)
+
+			if elapsed < test.expectedMinWait || elapsed > test.expectedMaxWait {
+				t.Fatalf("wait wrong. want between %s and %s, got=%s", test.expectedMinWait, test.expectedMaxWait, elapsed)
+			}
+
+			bytes, err := ioutil.ReadFile(lastRequestPath)
+			if !test.expectedRecorded {
+				if test.inputLastRequest > 0 && err != nil {
+					t.Fatalf("last request should be left as is. got: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("last request should be recorded. got: %s", err)
+			}
+			recorded, err := time.Parse(time.RFC3339Nano, string(bytes))
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if recorded.Before(start) {
+				t.Fatalf("last request should be updated. got: %s", recorded)
+			}
+		})
+	}
+}
+
+func TestClient_SetMinRequestGap_cancel(t *testing.T) {
+	cacheDirPath, err := ioutil.TempDir("", "atctest-request-gap")
+	if err != nil {
+		t.Fatalf("failed to create dummy cache dir: %s", err)
+	}
+	defer os.RemoveAll(cacheDirPath)
+	last := time.Now().Format(time.RFC3339Nano)
+	if err := ioutil.WriteFile(path.Join(cacheDirPath, lastRequestFileName), []byte(last), 0644); err != nil {
+		t.Fatalf("failed to write dummy last request: %s", err)
+	}
+
+	c := NewClient(dummyBaseURL, false, cacheDirPath, ioutil.Discard, ioutil.Discard)
+	c.SetMinRequestGap(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := c.GetSamplesContext(ctx, dummyBaseURL+"/contests/abc124/tasks/abc124_b"); err == nil {
+		t.Fatal("err should not be nil. got: nil")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("the wait should end with the context. got: %s", elapsed)
+	}
+}
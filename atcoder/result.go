@@ -0,0 +1,73 @@
+package atcoder
+
+import (
+	"time"
+)
+
+type Status int
+
+const (
+	StatusSuccess Status = iota
+	StatusFailure
+	StatusError
+	// StatusTimeout is the status of the sample whose run was killed after Options.Timeout.
+	StatusTimeout
+	// StatusRuntimeError is the status of the sample whose run exited with an unexpected code with Options.RuntimeError,
+	// like the RE verdict of AtCoder.
+	StatusRuntimeError
+	// StatusMemoryLimit is the status of the sample whose run used more memory than Options.MemoryLimit.
+	StatusMemoryLimit
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusSuccess:
+		return "SUCCESS"
+	case StatusFailure:
+		return "FAILURE"
+	case StatusError:
+		return "ERROR"
+	case StatusTimeout:
+		return "TIMEOUT"
+	case StatusRuntimeError:
+		return "RUNTIME ERROR"
+	case StatusMemoryLimit:
+		return "MEMORY LIMIT EXCEEDED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Result is the result of running the program for a sample.
+type Result struct {
+	// Index is the 0-based position of the sample in the samples given.
+	Index  int
+	Sample Sample
+	Status Status
+	// Actual is the output of the program. it is empty when the program failed or was killed.
+	Actual string
+	// Err is the error occurred while running the program. it is nil unless Status is StatusError or StatusRuntimeError.
+	Err error
+	// ExitCode is the exit code of the program. it is 0 when the program was not run to the end.
+	ExitCode int
+	// Stderr is the stderr of the program which exited with a non-zero code, e.g.) a stack trace.
+	Stderr string
+	// CheckerMessage is the output of Options.Checker, e.g.) why it rejects the output.
+	CheckerMessage string
+	// Elapsed is the wall-clock time taken to run the program.
+	Elapsed time.Duration
+	// MaxRSS is the peak memory usage of the program in bytes. 0 means it is not measured.
+	MaxRSS int64
+}
+
+func (r Result) Success() bool {
+	return r.Status == StatusSuccess
+}
+
+// Number returns the number of the sample, which is the position in the samples given if the sample has no number.
+func (r Result) Number() int {
+	if r.Sample.Number != 0 {
+		return r.Sample.Number
+	}
+	return r.Index + 1
+}
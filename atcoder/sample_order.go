@@ -0,0 +1,126 @@
+package atcoder
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// orderedHeadingSuffixes may follow the number of a heading, e.g.) "入力例 1 (N = 3)" or "Sample Input 1:".
+var orderedHeadingSuffixes = []string{"(", "（", "[", "【", ":", "："}
+
+// sampleElement is an input or output of a sample in the order of the page.
+type sampleElement struct {
+	// key is the heading without spaces, e.g.) "入力例1"
+	key  string
+	text string
+	// number is the number in the heading, or 0 if it has none, e.g.) "入力例"
+	number int
+	output bool
+}
+
+// addOrderedSample appends the pre or img element to the ordered elements if it is a sample.
+// the headings are matched less strictly than by isSampleHeading, since the pairs are made by the order and not by the headings.
+func addOrderedSample(ordered []sampleElement, s *goquery.Selection, lang Lang) []sampleElement {
+	element, ok := orderedSampleElement(normalizeHeading(findHeading(s)), lang)
+	if !ok {
+		return ordered
+	}
+	if goquery.NodeName(s) != "img" {
+		element.text = s.Text()
+		return append(ordered, element)
+	}
+
+	// a few problems give the sample output as an image instead of text
+	if !element.output {
+		return ordered
+	}
+	if last := len(ordered) - 1; last >= 0 && ordered[last].key == element.key {
+		if strings.TrimSpace(ordered[last].text) == "" {
+			ordered[last].text = imageElement
+		}
+		return ordered
+	}
+	element.text = imageElement
+	return append(ordered, element)
+}
+
+// orderedSampleElement parses the heading of a sample like "入力例1", "出力例" or "入力例1(N=3)",
+// but not of the other sections like "入力例の説明".
+func orderedSampleElement(key string, lang Lang) (sampleElement, bool) {
+	for _, h := range lang.headings() {
+		for _, prefix := range []string{h.input, h.output} {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			element := sampleElement{key: key, output: prefix == h.output}
+			rest := strings.TrimPrefix(key, prefix)
+			digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+			if !hasOrderedHeadingSuffix(rest[digits:]) {
+				return sampleElement{}, false
+			}
+			if digits > 0 {
+				n, err := strconv.Atoi(rest[:digits])
+				if err != nil || n < 1 {
+					return sampleElement{}, false
+				}
+				element.number = n
+			}
+			return element, true
+		}
+	}
+	return sampleElement{}, false
+}
+
+func hasOrderedHeadingSuffix(suffix string) bool {
+	if suffix == "" {
+		return true
+	}
+	for _, s := range orderedHeadingSuffixes {
+		if strings.HasPrefix(suffix, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// samplesInOrder pairs each input with the output right after it in the order of the page.
+// it returns false if the elements cannot be paired so, e.g.) an output is missing or given as an image,
+// so that the samples are built by their numbers instead, reporting what is wrong.
+func samplesInOrder(ordered []sampleElement) ([]Sample, bool) {
+	keys := make(map[string]string)
+	for _, e := range ordered {
+		keys[e.key] = e.text
+	}
+	h := headingsOf(keys)
+
+	var elements []sampleElement
+	for _, e := range ordered {
+		if h.has(e.key) {
+			elements = append(elements, e)
+		}
+	}
+	if len(elements) == 0 || len(elements)%2 != 0 {
+		return nil, false
+	}
+
+	var samples []Sample
+	numbers := make(map[int]bool)
+	for i := 0; i < len(elements); i += 2 {
+		input, output := elements[i], elements[i+1]
+		if input.output || !output.output || input.number != output.number || output.text == imageElement {
+			return nil, false
+		}
+		number := input.number
+		if number == 0 {
+			number = i/2 + 1
+		}
+		if numbers[number] {
+			return nil, false
+		}
+		numbers[number] = true
+		samples = append(samples, Sample{Input: input.text, Output: output.text, Number: number})
+	}
+	return samples, true
+}
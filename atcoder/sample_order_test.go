@@ -0,0 +1,120 @@
+package atcoder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderedSampleElement(t *testing.T) {
+	tests := []struct {
+		inputKey        string
+		expectedElement sampleElement
+		expectedOK      bool
+	}{
+		{inputKey: "入力例1", expectedElement: sampleElement{key: "入力例1", number: 1}, expectedOK: true},
+		{inputKey: "出力例", expectedElement: sampleElement{key: "出力例", output: true}, expectedOK: true},
+		{inputKey: "出力例12(N=3)", expectedElement: sampleElement{key: "出力例12(N=3)", number: 12, output: true}, expectedOK: true},
+		{inputKey: "SampleInput2:", expectedElement: sampleElement{key: "SampleInput2:", number: 2}, expectedOK: true},
+		{inputKey: "入力例の説明"},
+		{inputKey: "入力例1の説明"},
+		{inputKey: "配点"},
+	}
+	for _, test := range tests {
+		element, ok := orderedSampleElement(test.inputKey, LangAuto)
+		if ok != test.expectedOK {
+			t.Fatalf("whether '%s' is a sample wrong. want=%t, got=%t", test.inputKey, test.expectedOK, ok)
+		}
+		if element != test.expectedElement {
+			t.Fatalf("element of '%s' wrong. want=%+v, got=%+v", test.inputKey, test.expectedElement, element)
+		}
+	}
+}
+
+func TestSamplesInOrder(t *testing.T) {
+	tests := []struct {
+		name string
+
+		inputElements []sampleElement
+
+		expectedSamples []Sample
+		expectedOK      bool
+	}{
+		{
+			name: "success-numbered",
+			inputElements: []sampleElement{
+				{key: "入力例1", text: "1 2\n", number: 1},
+				{key: "出力例1", text: "3\n", number: 1, output: true},
+				{key: "入力例3", text: "3 4\n", number: 3},
+				{key: "出力例3", text: "7\n", number: 3, output: true},
+			},
+			expectedSamples: []Sample{
+				{Input: "1 2\n", Output: "3\n", Number: 1},
+				{Input: "3 4\n", Output: "7\n", Number: 3},
+			},
+			expectedOK: true,
+		},
+		{
+			name: "success-unnumbered",
+			inputElements: []sampleElement{
+				{key: "入力例", text: "1 2\n"},
+				{key: "出力例", text: "3\n", output: true},
+				{key: "入力例", text: "3 4\n"},
+				{key: "出力例", text: "7\n", output: true},
+			},
+			expectedSamples: []Sample{
+				{Input: "1 2\n", Output: "3\n", Number: 1},
+				{Input: "3 4\n", Output: "7\n", Number: 2},
+			},
+			expectedOK: true,
+		},
+		{
+			name: "success-other_language_left_out",
+			inputElements: []sampleElement{
+				{key: "入力例1", text: "1 2\n", number: 1},
+				{key: "出力例1", text: "3\n", number: 1, output: true},
+				{key: "SampleInput1", text: "1 2\n", number: 1},
+				{key: "SampleOutput1", text: "3\n", number: 1, output: true},
+			},
+			expectedSamples: []Sample{
+				{Input: "1 2\n", Output: "3\n", Number: 1},
+			},
+			expectedOK: true,
+		},
+		{
+			name: "failure-output_missing",
+			inputElements: []sampleElement{
+				{key: "入力例1", text: "1 2\n", number: 1},
+				{key: "入力例2", text: "3 4\n", number: 2},
+				{key: "出力例2", text: "7\n", number: 2, output: true},
+			},
+		},
+		{
+			name: "failure-numbers_mismatched",
+			inputElements: []sampleElement{
+				{key: "入力例1", text: "1 2\n", number: 1},
+				{key: "出力例2", text: "7\n", number: 2, output: true},
+			},
+		},
+		{
+			name: "failure-output_as_image",
+			inputElements: []sampleElement{
+				{key: "入力例1", text: "1 2\n", number: 1},
+				{key: "出力例1", text: imageElement, number: 1, output: true},
+			},
+		},
+		{
+			name: "failure-no_element",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			samples, ok := samplesInOrder(test.inputElements)
+			if ok != test.expectedOK {
+				t.Fatalf("ok wrong. want=%t, got=%t", test.expectedOK, ok)
+			}
+			if !reflect.DeepEqual(samples, test.expectedSamples) {
+				t.Fatalf("samples wrong. want=%+v, got=%+v", test.expectedSamples, samples)
+			}
+		})
+	}
+}
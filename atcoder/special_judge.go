@@ -0,0 +1,47 @@
+package atcoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mui87/atctest/commander"
+)
+
+// checkerWrongAnswer is the exit code of the checker which rejects the output.
+// the other non-zero codes mean the checker itself failed, e.g.) 127 for the command not found.
+const checkerWrongAnswer = 1
+
+// judge runs the checker of Options.Checker like the special judge of AtCoder, to accept any of the valid outputs.
+// the checker is given the paths of the files of the input, the expected output and the actual output as its arguments
+// in this order, and exits with 0 if it accepts the output, or with checkerWrongAnswer if it rejects it.
+// the checker is killed when ctx is done, e.g.) by Options.Timeout, as the program is.
+// the stdout and stderr of the checker are returned as its message, e.g.) why it rejects the output.
+func judge(ctx context.Context, checker string, sample Sample, actual string) (bool, string, error) {
+	var paths []string
+	for _, file := range []struct{ kind, content string }{
+		{kind: "input", content: sample.Input},
+		{kind: "expected", content: sample.Output},
+		{kind: "actual", content: actual},
+	} {
+		path, remove, err := writeTempFile(file.kind, file.content)
+		if err != nil {
+			return false, "", err
+		}
+		defer remove()
+		paths = append(paths, commander.Quote(path))
+	}
+
+	out, err := commander.NewExternal().Run(ctx, checker+" "+strings.Join(paths, " "), "")
+	message := out + stderrOf(err)
+	var exitErr *commander.ExitError
+	switch {
+	case err == nil:
+		return true, message, nil
+	case errors.As(err, &exitErr) && exitErr.Code == checkerWrongAnswer:
+		return false, message, nil
+	default:
+		return false, message, fmt.Errorf("failed to run the checker '%s': %s", checker, err)
+	}
+}
@@ -0,0 +1,127 @@
+package atcoder
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mui87/atctest/commander"
+)
+
+// dummyChecker accepts the output which is within 1 of the expected one, given the arguments as $1, $2 and $3.
+const dummyChecker = `sh -c 'a=$(cat "$3"); e=$(cat "$2"); if [ $((a - e)) -le 1 ] && [ $((e - a)) -le 1 ]; then exit 0; fi; echo "$a is too far from $e"; exit 1' judge`
+
+func TestJudge(t *testing.T) {
+	tests := []struct {
+		name         string
+		inputChecker string
+		inputActual  string
+
+		expectedAccepted bool
+		expectedMessage  string
+		expectedErrMsg   string
+	}{
+		{
+			name:             "accepted",
+			inputChecker:     dummyChecker,
+			inputActual:      "4\n",
+			expectedAccepted: true,
+		},
+		{
+			name:            "rejected",
+			inputChecker:    dummyChecker,
+			inputActual:     "5\n",
+			expectedMessage: "5 is too far from 3\n",
+		},
+		{
+			name:            "input given",
+			inputChecker:    `sh -c 'cat "$1"; exit 1' judge`,
+			inputActual:     "3\n",
+			expectedMessage: "1 2\n",
+		},
+		{
+			name:           "failure-checker not found",
+			inputChecker:   "./no-such-judge",
+			inputActual:    "3\n",
+			expectedErrMsg: "failed to run the checker './no-such-judge'",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			accepted, message, err := judge(context.Background(), test.inputChecker, Sample{Input: "1 2\n", Output: "3\n"}, test.inputActual)
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				if !strings.Contains(err.Error(), test.expectedErrMsg) {
+					t.Fatalf("expect '%s' to contain '%s'", err.Error(), test.expectedErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if accepted != test.expectedAccepted {
+				t.Fatalf("accepted wrong. want=%t, got=%t", test.expectedAccepted, accepted)
+			}
+			if message != test.expectedMessage {
+				t.Fatalf("message wrong. want=%q, got=%q", test.expectedMessage, message)
+			}
+		})
+	}
+}
+
+func TestCheckSamples_checker(t *testing.T) {
+	samples := []Sample{
+		{Input: "1 2\n", Output: "3\n"},
+	}
+
+	results := CheckSamples(commander.NewExternal(), "echo 4", samples, Options{Checker: dummyChecker})
+	if results[0].Status != StatusSuccess {
+		t.Fatalf("status wrong. want=%s, got=%s: %+v", StatusSuccess, results[0].Status, results[0])
+	}
+
+	results = CheckSamples(commander.NewExternal(), "echo 9", samples, Options{Checker: dummyChecker})
+	if results[0].Status != StatusFailure {
+		t.Fatalf("status wrong. want=%s, got=%s: %+v", StatusFailure, results[0].Status, results[0])
+	}
+	if results[0].CheckerMessage != "9 is too far from 3\n" {
+		t.Fatalf("checker message wrong. got: %q", results[0].CheckerMessage)
+	}
+}
+
+func TestCheckSamples_checkerTimeout(t *testing.T) {
+	samples := []Sample{
+		{Input: "1 2\n", Output: "3\n"},
+	}
+
+	results := CheckSamples(commander.NewExternal(), "echo 3", samples, Options{Checker: `sh -c 'sleep 10' judge`, Timeout: 200 * time.Millisecond})
+	if results[0].Status != StatusTimeout {
+		t.Fatalf("status wrong. want=%s, got=%s: %+v", StatusTimeout, results[0].Status, results[0])
+	}
+}
+
+func TestJudge_quotedPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atctest-judge")
+	if err != nil {
+		t.Fatalf("failed to create dummy dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	tmpDir := filepath.Join(dir, "it's a dir")
+	if err := os.Mkdir(tmpDir, 0777); err != nil {
+		t.Fatalf("failed to create dummy dir: %s", err)
+	}
+	t.Setenv("TMPDIR", tmpDir)
+
+	accepted, message, err := judge(context.Background(), dummyChecker, Sample{Input: "1 2\n", Output: "3\n"}, "3\n")
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if !accepted {
+		t.Fatalf("the output should be accepted. message: %s", message)
+	}
+}
@@ -0,0 +1,101 @@
+package atcoder
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mui87/atctest/commander"
+)
+
+// SampleIterator yields samples one by one, so that huge test sets need not be loaded into memory at once.
+type SampleIterator interface {
+	// Next returns the next sample, or io.EOF when there are no more samples.
+	Next() (Sample, error)
+}
+
+// dirSampleIterator reads the test cases laid out as the official archives of AtCoder,
+// i.e. dir/in/NAME for the input and dir/out/NAME for the expected output.
+type dirSampleIterator struct {
+	dir   string
+	names []string
+	next  int
+}
+
+// NewDirSampleIterator lists the test cases in the directory. their contents are read only when they are iterated.
+func NewDirSampleIterator(dir string) (SampleIterator, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(dir, "in"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list test cases: %s", err)
+	}
+
+	var names []string
+	for _, info := range infos {
+		if info.Mode().IsRegular() {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return &dirSampleIterator{dir: dir, names: names}, nil
+}
+
+func (d *dirSampleIterator) Next() (Sample, error) {
+	if d.next >= len(d.names) {
+		return Sample{}, io.EOF
+	}
+	name := d.names[d.next]
+	d.next++
+
+	input, err := ioutil.ReadFile(filepath.Join(d.dir, "in", name))
+	if err != nil {
+		return Sample{}, err
+	}
+	output, err := ioutil.ReadFile(filepath.Join(d.dir, "out", name))
+	if os.IsNotExist(err) {
+		return Sample{}, fmt.Errorf("expected output of test case %s not found", name)
+	} else if err != nil {
+		return Sample{}, err
+	}
+
+	return Sample{Input: string(input), Output: string(output), Number: d.next}, nil
+}
+
+// CheckIterator runs the command for each sample yielded by the iterator.
+// unlike CheckSamples, it keeps no results but the numbers of passed and all the samples.
+// the samples are run one at a time since they are read lazily, so opts.Jobs is ignored.
+// with opts.FailFast, it stops after the first sample which does not pass, which is counted in the total.
+// progress, if given, is called when each sample starts and as soon as its result is available.
+func CheckIterator(cmd commander.Commander, command string, it SampleIterator, opts Options, progress func(Event)) (int, int, error) {
+	passed, total := 0, 0
+	for ; ; total++ {
+		sample, err := it.Next()
+		if err == io.EOF {
+			return passed, total, nil
+		} else if err != nil {
+			return passed, total, err
+		}
+
+		if total > 0 && opts.DelayBetweenSamples > 0 {
+			time.Sleep(opts.DelayBetweenSamples)
+		}
+
+		if progress != nil {
+			progress(Event{Type: EventStarted, Index: total, Sample: sample})
+		}
+		result := checkOne(cmd, command, sample, opts)
+		result.Index = total
+		if progress != nil {
+			progress(Event{Type: EventFinished, Index: total, Sample: sample, Result: &result})
+		}
+		if result.Success() {
+			passed++
+		} else if opts.FailFast {
+			return passed, total + 1, nil
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package atcoder
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestCheckIterator(t *testing.T) {
+	it, err := NewDirSampleIterator(path.Join("testdata", "testcases"))
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	cmd := &testCommander{results: []commandResult{
+		{output: "3\n"},
+		{output: "999\n"},
+		{output: "10\n"},
+	}}
+	var finished []int
+	passed, total, err := CheckIterator(cmd, dummyRawCommand, it, Options{}, func(e Event) {
+		if e.Type == EventFinished {
+			finished = append(finished, e.Result.Number())
+		}
+	})
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if passed != 2 || total != 3 {
+		t.Fatalf("counts wrong. want=(2, 3), got=(%d, %d)", passed, total)
+	}
+	if len(finished) != 3 || finished[0] != 1 || finished[2] != 3 {
+		t.Fatalf("finished samples wrong. got: %v", finished)
+	}
+	if cmd.stdins[1] != "100 200\n" {
+		t.Fatalf("2nd input wrong. got: %q", cmd.stdins[1])
+	}
+}
+
+func TestCheckIterator_failFast(t *testing.T) {
+	it, err := NewDirSampleIterator(path.Join("testdata", "testcases"))
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+
+	cmd := &testCommander{results: []commandResult{
+		{output: "3\n"},
+		{output: "999\n"},
+		{output: "10\n"},
+	}}
+	var started []int
+	passed, total, err := CheckIterator(cmd, dummyRawCommand, it, Options{FailFast: true}, func(e Event) {
+		if e.Type == EventStarted {
+			started = append(started, e.Index)
+		}
+	})
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if passed != 1 || total != 2 {
+		t.Fatalf("counts wrong. want=(1, 2), got=(%d, %d)", passed, total)
+	}
+	if len(started) != 2 || len(cmd.stdins) != 2 {
+		t.Fatalf("the 3rd sample should not be run. started: %v, stdins: %q", started, cmd.stdins)
+	}
+}
+
+func TestCheckIterator_outputMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atctest-testcases")
+	if err != nil {
+		t.Fatalf("failed to create dummy dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	for _, sub := range []string{"in", "out"} {
+		if err := os.MkdirAll(path.Join(dir, sub), 0777); err != nil {
+			t.Fatalf("failed to create dummy dir: %s", err)
+		}
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "in", "01.txt"), []byte("1\n"), 0644); err != nil {
+		t.Fatalf("failed to write test case: %s", err)
+	}
+
+	it, err := NewDirSampleIterator(dir)
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	_, _, err = CheckIterator(&testCommander{}, dummyRawCommand, it, Options{}, nil)
+	if err == nil {
+		t.Fatal("err should not be nil. got: nil")
+	}
+	if !strings.Contains(err.Error(), "expected output of test case 01.txt not found") {
+		t.Fatalf("expect '%s' to contain '%s'", err.Error(), "expected output of test case 01.txt not found")
+	}
+}
@@ -0,0 +1,24 @@
+package atcoder
+
+import "fmt"
+
+// SetVerbose makes the client log the URLs it visits and the cache hits to errStream, for troubleshooting.
+func (c *Client) SetVerbose(verbose bool) {
+	c.verbose = verbose
+}
+
+func (c *Client) logf(format string, args ...interface{}) {
+	if !c.verbose {
+		return
+	}
+	_, _ = fmt.Fprintf(c.errStream, "[VERBOSE] "+format+"\n", args...)
+}
+
+// logCache logs whether the cache of the URL is hit.
+func (c *Client) logCache(hit bool, pageURL string) {
+	if hit {
+		c.logf("cache hit: %s", pageURL)
+	} else {
+		c.logf("cache miss: %s", pageURL)
+	}
+}
@@ -0,0 +1,76 @@
+package atcoder
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gocolly/colly"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestClient_SetVerbose(t *testing.T) {
+	const problemURL = dummyBaseURL + "/contests/abc124/tasks/abc124_b"
+
+	tests := []struct {
+		name           string
+		inputVerbose   bool
+		expectedLogs   []string
+		expectedNoLogs bool
+	}{
+		{
+			name:         "verbose",
+			inputVerbose: true,
+			expectedLogs: []string{
+				"[VERBOSE] cache miss: " + problemURL + "\n[VERBOSE] GET " + problemURL + "\n",
+				"[VERBOSE] cache hit: " + problemURL + "\n",
+			},
+		},
+		{
+			name:           "not verbose",
+			expectedNoLogs: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			html, err := ioutil.ReadFile(path.Join("testdata", "problem", "abc124b.html"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer gock.Off()
+			gock.New(dummyBaseURL).
+				Get("/contests/abc124/tasks/abc124_b").
+				Reply(http.StatusOK).
+				AddHeader("Content-Type", "text/html").
+				BodyString(string(html))
+			defer func() {
+				_ = os.RemoveAll(dummyCacheDirPath)
+			}()
+
+			var errBuff bytes.Buffer
+			c := &Client{baseURL: dummyBaseURL, collector: colly.NewCollector(), useCache: true, cacheDirPath: dummyCacheDirPath, errStream: &errBuff}
+			c.SetVerbose(test.inputVerbose)
+
+			// the second call hits the cache written by the first one
+			for i := 0; i < 2; i++ {
+				if _, err := c.GetSamples(problemURL); err != nil {
+					t.Fatalf("err should be nil. got: %s", err)
+				}
+			}
+
+			if test.expectedNoLogs && errBuff.String() != "" {
+				t.Fatalf("errStream should be empty. got: %s", errBuff.String())
+			}
+			for _, expected := range test.expectedLogs {
+				if !strings.Contains(errBuff.String(), expected) {
+					t.Fatalf("expect '%s' to contain '%s'", errBuff.String(), expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package browser
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// Open opens the url with the default browser of the OS.
+func Open(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
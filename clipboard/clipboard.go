@@ -0,0 +1,38 @@
+package clipboard
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// linuxCommands are tried in order since which one is available depends on the desktop environment.
+var linuxCommands = [][]string{
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+}
+
+// Copy copies the text to the clipboard of the OS.
+func Copy(text string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("pbcopy")
+	case "windows":
+		cmd = exec.Command("clip")
+	default:
+		for _, args := range linuxCommands {
+			if _, err := exec.LookPath(args[0]); err == nil {
+				cmd = exec.Command(args[0], args[1:]...)
+				break
+			}
+		}
+		if cmd == nil {
+			return errors.New("no clipboard command found. install wl-copy, xclip or xsel")
+		}
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
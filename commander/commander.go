@@ -2,13 +2,20 @@ package commander
 
 import (
 	"bytes"
-	"fmt"
+	"context"
 	"os/exec"
 	"strings"
+	"time"
 )
 
+// waitDelay is how long Run waits for the output to be closed after the command is killed,
+// since a process left by the command, e.g.) './a.out' of 'g++ a.cpp && ./a.out', may keep it open.
+const waitDelay = time.Second
+
 type Commander interface {
-	Run(rawCommand, stdin string) (string, error)
+	// Run runs the command with the stdin, killing it when ctx is done.
+	// the output is also returned with *ExitError when the command exits with a non-zero code.
+	Run(ctx context.Context, rawCommand, stdin string) (string, error)
 }
 
 type External struct{}
@@ -17,20 +24,44 @@ func NewExternal() *External {
 	return &External{}
 }
 
-func (e *External) Run(rawCommand, stdin string) (string, error) {
+func (e *External) Run(ctx context.Context, rawCommand, stdin string) (string, error) {
+	out, _, err := e.RunWithUsage(ctx, rawCommand, stdin)
+	return out, err
+}
+
+// RunWithUsage is Run which also returns the peak memory usage of the command.
+func (e *External) RunWithUsage(ctx context.Context, rawCommand, stdin string) (string, Usage, error) {
 	var errBuf bytes.Buffer
 
-	cmd := NewCommand(rawCommand)
+	cmd := NewCommandContext(ctx, rawCommand)
 	cmd.Stdin = strings.NewReader(stdin)
 	cmd.Stderr = &errBuf
 
 	out, err := cmd.Output()
+	usage := Usage{MaxRSS: maxRSS(cmd.ProcessState)}
 	if err != nil {
-		return "", fmt.Errorf("%s: %s", err.Error(), errBuf.String())
+		return string(out), usage, wrapError(ctx, err, errBuf.String())
+	}
+	return string(out), usage, nil
+}
+
+// Quote quotes the word for bash with single quotes unless it consists only of safe characters,
+// e.g.) to put a path into a command.
+func Quote(word string) string {
+	if word != "" && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=,@%+") == "" {
+		return word
 	}
-	return string(out), nil
+	return "'" + strings.Replace(word, "'", `'\''`, -1) + "'"
 }
 
 func NewCommand(rawCommand string) *exec.Cmd {
 	return exec.Command("/bin/bash", "-c", rawCommand)
 }
+
+// NewCommandContext returns the command which is killed with the processes it started when ctx is done.
+func NewCommandContext(ctx context.Context, rawCommand string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "/bin/bash", "-c", rawCommand)
+	killGroupOnCancel(cmd)
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
@@ -0,0 +1,106 @@
+package commander
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestExternal_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputCommand   string
+		inputStdin     string
+		inputTimeout   time.Duration
+		expectedOutput string
+		expectedErr    bool
+	}{
+		{
+			name:           "stdin is given",
+			inputCommand:   "cat",
+			inputStdin:     "1 2\n3\n",
+			inputTimeout:   time.Minute,
+			expectedOutput: "1 2\n3\n",
+		},
+		{
+			name:         "command fails",
+			inputCommand: "exit 1",
+			inputTimeout: time.Minute,
+			expectedErr:  true,
+		},
+		{
+			name:         "command is killed with the processes it started",
+			inputCommand: "sleep 10 && echo done",
+			inputTimeout: 100 * time.Millisecond,
+			expectedErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), test.inputTimeout)
+			defer cancel()
+
+			start := time.Now()
+			output, err := NewExternal().Run(ctx, test.inputCommand, test.inputStdin)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Fatalf("command should be killed. took %s", elapsed)
+			}
+			if test.expectedErr {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if output != test.expectedOutput {
+				t.Fatalf("output wrong. want=%q, got=%q", test.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestExternal_Run_exitCode(t *testing.T) {
+	output, err := NewExternal().Run(context.Background(), "echo out; echo err >&2; exit 3", "")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("err should be *ExitError. got: %v", err)
+	}
+	if exitErr.Code != 3 || exitErr.Stderr != "err\n" {
+		t.Fatalf("exit error wrong. want=(3, %q), got=(%d, %q)", "err\n", exitErr.Code, exitErr.Stderr)
+	}
+	if output != "out\n" {
+		t.Fatalf("output wrong. want=%q, got=%q", "out\n", output)
+	}
+
+	_, err = NewExternal().Run(context.Background(), "echo err >&2; kill -SEGV $$", "")
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("err of the command killed by a signal should be *ExitError. got: %v", err)
+	}
+	if exitErr.Code != 128+int(syscall.SIGSEGV) || exitErr.Signal != syscall.SIGSEGV || exitErr.Stderr != "err\n" {
+		t.Fatalf("exit error wrong. want=(%d, %s, %q), got=(%d, %s, %q)", 128+int(syscall.SIGSEGV), syscall.SIGSEGV, "err\n", exitErr.Code, exitErr.Signal, exitErr.Stderr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := NewExternal().Run(ctx, "sleep 10", ""); errors.As(err, &exitErr) {
+		t.Fatalf("err of the killed command should not be *ExitError. got: %v", err)
+	}
+}
+
+func TestExternal_RunWithUsage(t *testing.T) {
+	if !MemoryMeasured {
+		t.Skip("the memory usage is not measured on this platform")
+	}
+	_, usage, err := NewExternal().RunWithUsage(context.Background(), "cat", "1 2\n")
+	if err != nil {
+		t.Fatalf("err should be nil. got: %s", err)
+	}
+	if usage.MaxRSS <= 0 {
+		t.Fatalf("peak memory usage should be measured. got: %d", usage.MaxRSS)
+	}
+}
@@ -0,0 +1,66 @@
+package commander
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Transcoding runs commands with another commander, converting the stdin from UTF-8 to the encoding
+// and the output back to UTF-8, for toolchains which expect an encoding other than UTF-8, e.g.) Shift_JIS on Windows.
+type Transcoding struct {
+	commander Commander
+	encoding  encoding.Encoding
+}
+
+// NewTranscoding returns a commander for the encoding of the name like 'shift_jis' or 'euc-jp'.
+func NewTranscoding(commander Commander, name string) (*Transcoding, error) {
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown encoding '%s'", name)
+	}
+	return &Transcoding{commander: commander, encoding: enc}, nil
+}
+
+// IsUTF8 reports whether the encoding of the name is UTF-8, with which no conversion is needed.
+func IsUTF8(name string) bool {
+	enc, err := htmlindex.Get(name)
+	return err == nil && enc == unicode.UTF8
+}
+
+func (t *Transcoding) Run(ctx context.Context, rawCommand, stdin string) (string, error) {
+	out, _, err := t.RunWithUsage(ctx, rawCommand, stdin)
+	return out, err
+}
+
+// RunWithUsage is Run which also returns the usage measured by the underlying commander, if it does.
+func (t *Transcoding) RunWithUsage(ctx context.Context, rawCommand, stdin string) (string, Usage, error) {
+	encoded, err := t.encoding.NewEncoder().String(stdin)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("failed to convert the input to %s: %s", t.name(), err)
+	}
+
+	out, usage, runErr := RunWithUsage(ctx, t.commander, rawCommand, encoded)
+	var exitErr *ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return "", usage, runErr
+	}
+
+	decoded, err := t.encoding.NewDecoder().String(out)
+	if err != nil {
+		return "", usage, fmt.Errorf("failed to convert the output from %s: %s", t.name(), err)
+	}
+	return decoded, usage, runErr
+}
+
+func (t *Transcoding) name() string {
+	name, err := htmlindex.Name(t.encoding)
+	if err != nil {
+		return "the encoding"
+	}
+	return name
+}
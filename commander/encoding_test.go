@@ -0,0 +1,93 @@
+package commander
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranscoding_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputEncoding  string
+		inputCommand   string
+		inputStdin     string
+		expectedOutput string
+		expectedErr    bool
+	}{
+		{
+			name:           "input is given in shift_jis",
+			inputEncoding:  "shift_jis",
+			inputCommand:   "wc -c | tr -d ' '",
+			inputStdin:     "あ\n",
+			expectedOutput: "3\n",
+		},
+		{
+			name:           "output is converted from shift_jis",
+			inputEncoding:  "shift_jis",
+			inputCommand:   `printf '\x82\xa0\n'`,
+			expectedOutput: "あ\n",
+		},
+		{
+			name:           "round trip in euc-jp",
+			inputEncoding:  "euc-jp",
+			inputCommand:   "cat",
+			inputStdin:     "高橋 くん\n",
+			expectedOutput: "高橋 くん\n",
+		},
+		{
+			name:           "input is given in utf-8 as is",
+			inputEncoding:  "utf-8",
+			inputCommand:   "wc -c | tr -d ' '",
+			inputStdin:     "あ\n",
+			expectedOutput: "4\n",
+		},
+		{
+			name:          "command fails",
+			inputEncoding: "shift_jis",
+			inputCommand:  "exit 1",
+			expectedErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := NewTranscoding(NewExternal(), test.inputEncoding)
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+
+			output, err := c.Run(context.Background(), test.inputCommand, test.inputStdin)
+			if test.expectedErr {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if output != test.expectedOutput {
+				t.Fatalf("output wrong. want=%q, got=%q", test.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestNewTranscoding_unknown(t *testing.T) {
+	_, err := NewTranscoding(NewExternal(), "no-such-encoding")
+	if err == nil {
+		t.Fatal("err should not be nil. got: nil")
+	}
+	if !strings.Contains(err.Error(), "no-such-encoding") {
+		t.Fatalf("expect '%s' to contain '%s'", err.Error(), "no-such-encoding")
+	}
+}
+
+func TestIsUTF8(t *testing.T) {
+	for name, expected := range map[string]bool{"utf-8": true, "UTF8": true, "shift_jis": false, "unknown": false} {
+		if IsUTF8(name) != expected {
+			t.Fatalf("IsUTF8(%q) wrong. want=%t", name, expected)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package commander
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// signalExitCodeBase is added to the signal which killed the command to make its exit code, as shells do.
+const signalExitCodeBase = 128
+
+// ExitError is the error for the command which exited with a non-zero code, or was killed by a signal by itself.
+// Run returns the output of the command along with it, e.g.) for the programs which exit with a code on purpose.
+type ExitError struct {
+	// Code is the exit code, which is 128+signal for the command killed by a signal, e.g.) 139 for a segmentation fault.
+	Code int
+	// Signal is the signal which killed the command, or 0 if it exited by itself.
+	Signal syscall.Signal
+	// Stderr is what the command wrote to stderr, e.g.) a stack trace. it is not in the message of the error.
+	Stderr string
+}
+
+func (e *ExitError) Error() string {
+	if e.Signal != 0 {
+		return fmt.Sprintf("%s (exit status %d)", e.Signal, e.Code)
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// wrapError returns ExitError for the command which exited or was killed by a signal, e.g.) a segmentation fault,
+// with the stderr of the command. the other errors, e.g.) killed as ctx is done, keep the message of os/exec.
+func wrapError(ctx context.Context, err error, stderr string) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		if exitErr.ExitCode() > 0 {
+			return &ExitError{Code: exitErr.ExitCode(), Stderr: stderr}
+		}
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return &ExitError{Code: signalExitCodeBase + int(status.Signal()), Signal: status.Signal(), Stderr: stderr}
+		}
+	}
+	if stderr == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %s", err.Error(), stderr)
+}
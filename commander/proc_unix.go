@@ -0,0 +1,16 @@
+//go:build !windows
+
+package commander
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killGroupOnCancel runs the command in its own process group to kill the whole group on cancel.
+func killGroupOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
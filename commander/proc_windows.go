@@ -0,0 +1,10 @@
+//go:build windows
+
+package commander
+
+import (
+	"os/exec"
+)
+
+// killGroupOnCancel leaves the command as is since Windows has no process groups to kill at once.
+func killGroupOnCancel(cmd *exec.Cmd) {}
@@ -0,0 +1,68 @@
+package commander
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/creack/pty"
+)
+
+// PTY runs commands with their stdout attached to a pseudo-terminal,
+// so that programs which buffer their output differently when it is not a terminal behave as on a terminal.
+type PTY struct{}
+
+// NewPTY returns an error on the platforms which have no pseudo-terminal, e.g.) Windows.
+func NewPTY() (*PTY, error) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		if errors.Is(err, pty.ErrUnsupported) {
+			return nil, errors.New("pseudo-terminal is not supported on this platform")
+		}
+		return nil, fmt.Errorf("failed to open pseudo-terminal: %s", err)
+	}
+	_ = slave.Close()
+	_ = master.Close()
+	return &PTY{}, nil
+}
+
+func (p *PTY) Run(ctx context.Context, rawCommand, stdin string) (string, error) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open pseudo-terminal: %s", err)
+	}
+	defer master.Close()
+
+	var errBuf bytes.Buffer
+	cmd := NewCommandContext(ctx, rawCommand)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = slave
+	cmd.Stderr = &errBuf
+
+	if err := cmd.Start(); err != nil {
+		_ = slave.Close()
+		return "", err
+	}
+	// the child has its own copy. closing ours lets reading the master end when the child exits.
+	_ = slave.Close()
+
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		// reading the master fails with EIO instead of EOF after the slave is closed on Linux
+		_, _ = io.Copy(&out, master)
+		close(done)
+	}()
+
+	err = cmd.Wait()
+	<-done
+	// the terminal translates "\n" into "\r\n"
+	output := strings.Replace(out.String(), "\r\n", "\n", -1)
+	if err != nil {
+		return output, wrapError(ctx, err, errBuf.String())
+	}
+	return output, nil
+}
@@ -0,0 +1,56 @@
+package commander
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPTY_Run(t *testing.T) {
+	p, err := NewPTY()
+	if err != nil {
+		t.Skipf("pseudo-terminal is unavailable: %s", err)
+	}
+
+	tests := []struct {
+		name           string
+		inputCommand   string
+		inputStdin     string
+		expectedOutput string
+		expectedErr    bool
+	}{
+		{
+			name:           "stdout is a terminal",
+			inputCommand:   "if [ -t 1 ]; then echo tty; else echo pipe; fi",
+			expectedOutput: "tty\n",
+		},
+		{
+			name:           "stdin is given",
+			inputCommand:   "cat",
+			inputStdin:     "1 2\n3\n",
+			expectedOutput: "1 2\n3\n",
+		},
+		{
+			name:         "command fails",
+			inputCommand: "exit 1",
+			expectedErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := p.Run(context.Background(), test.inputCommand, test.inputStdin)
+			if test.expectedErr {
+				if err == nil {
+					t.Fatal("err should not be nil. got: nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err should be nil. got: %s", err)
+			}
+			if output != test.expectedOutput {
+				t.Fatalf("output wrong. want=%q, got=%q", test.expectedOutput, output)
+			}
+		})
+	}
+}
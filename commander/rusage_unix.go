@@ -0,0 +1,28 @@
+//go:build !windows
+
+package commander
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// MemoryMeasured reports whether the peak memory usage of the commands is measured on this platform.
+const MemoryMeasured = true
+
+// maxRSS returns the peak memory usage of the process in bytes, including the children it waited for.
+func maxRSS(state *os.ProcessState) int64 {
+	if state == nil {
+		return 0
+	}
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	// it is in bytes on macOS, and in kilobytes on the others
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		return int64(rusage.Maxrss)
+	}
+	return int64(rusage.Maxrss) * 1024
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package commander
+
+import (
+	"os"
+)
+
+// MemoryMeasured reports whether the peak memory usage of the commands is measured on this platform.
+// Windows has no rusage, so it is not.
+const MemoryMeasured = false
+
+func maxRSS(state *os.ProcessState) int64 {
+	return 0
+}
@@ -0,0 +1,25 @@
+package commander
+
+import (
+	"context"
+)
+
+// Usage is the resource usage of a command.
+type Usage struct {
+	// MaxRSS is the peak memory usage of the command in bytes. 0 means it is not measured.
+	MaxRSS int64
+}
+
+// UsageRunner is the Commander which also measures the resource usage of the command.
+type UsageRunner interface {
+	RunWithUsage(ctx context.Context, rawCommand, stdin string) (string, Usage, error)
+}
+
+// RunWithUsage runs the command, measuring its resource usage if the commander is UsageRunner.
+func RunWithUsage(ctx context.Context, c Commander, rawCommand, stdin string) (string, Usage, error) {
+	if r, ok := c.(UsageRunner); ok {
+		return r.RunWithUsage(ctx, rawCommand, stdin)
+	}
+	out, err := c.Run(ctx, rawCommand, stdin)
+	return out, Usage{}, err
+}
@@ -1,25 +1,36 @@
 module github.com/mui87/atctest
 
+go 1.21
+
+require (
+	github.com/PuerkitoBio/goquery v1.5.0
+	github.com/creack/pty v1.1.21
+	github.com/fatih/color v1.7.0
+	github.com/gocolly/colly v1.2.1-0.20190408114448-b3d99101c625
+	github.com/mattn/go-isatty v0.0.7
+	github.com/mitchellh/go-homedir v1.1.0
+	golang.org/x/term v0.0.0-20201126162022-7de9c90e9dd1
+	golang.org/x/text v0.3.2
+	gopkg.in/h2non/gock.v1 v1.0.14
+)
+
 require (
-	github.com/PuerkitoBio/goquery v1.5.0 // indirect
+	github.com/andybalholm/cascadia v1.0.0 // indirect
 	github.com/antchfx/htmlquery v1.0.0 // indirect
 	github.com/antchfx/xmlquery v1.0.0 // indirect
 	github.com/antchfx/xpath v0.0.0-20190319080838-ce1d48779e67 // indirect
-	github.com/fatih/color v1.7.0
 	github.com/gobwas/glob v0.2.3 // indirect
-	github.com/gocolly/colly v1.2.1-0.20190408114448-b3d99101c625
 	github.com/golang/protobuf v1.3.1 // indirect
+	github.com/h2non/parth v0.0.0-20190131123155-b4df798d6542 // indirect
 	github.com/kennygrant/sanitize v1.2.4 // indirect
 	github.com/mattn/go-colorable v0.1.1 // indirect
-	github.com/mattn/go-isatty v0.0.7 // indirect
-	github.com/mitchellh/go-homedir v1.1.0
+	github.com/nbio/st v0.0.0-20140626010706-e9e8d9816f32 // indirect
 	github.com/saintfish/chardet v0.0.0-20120816061221-3af4cd4741ca // indirect
 	github.com/temoto/robotstxt v0.0.0-20180810133444-97ee4a9ee6ea // indirect
 	golang.org/x/crypto v0.0.0-20190426145343-a29dc8fdc734 // indirect
 	golang.org/x/net v0.0.0-20190424112056-4829fb13d2c6 // indirect
-	golang.org/x/sys v0.0.0-20190429190828-d89cdac9e872 // indirect
-	golang.org/x/text v0.3.2 // indirect
+	golang.org/x/sync v0.0.0-20190423024810-112230192c58 // indirect
+	golang.org/x/sys v0.0.0-20201119102817-f84b799fce68 // indirect
 	golang.org/x/tools v0.0.0-20190430004104-b9fed7929fc1 // indirect
 	google.golang.org/appengine v1.5.0 // indirect
-	gopkg.in/h2non/gock.v1 v1.0.14
 )
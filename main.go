@@ -22,8 +22,11 @@ func run() int {
 		_, _ = fmt.Fprintln(os.Stderr, "[ERROR] "+err.Error())
 		return exitCodeErr
 	}
+	defer a.Close()
 
-	if err := a.Run(); err != nil {
+	if err := a.Run(); err == app.ErrSamplesFailed {
+		return exitCodeErr
+	} else if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, "[ERROR] "+err.Error())
 		return exitCodeErr
 	}